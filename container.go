@@ -0,0 +1,128 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// Container creates a [Container] handle for an existing container, referenced by name or ID.
+func (d *DockerRef) Container(nameOrID string) *Container {
+	anyBlankPanic(strmap{"nameOrID": &nameOrID})
+	return &Container{d: d, id: nameOrID}
+}
+
+// Container is a handle to a container, which is returned from [DockerRun.Start].
+// It's used to interact with the container without passing its name between Tasks.
+type Container struct {
+	d    *DockerRef
+	id   string
+	name string
+}
+
+// ID returns the ID of the container.
+// If the handle was created with [DockerRef.Container], then this is the name or ID it was created with.
+func (c *Container) ID() string {
+	return c.id
+}
+
+// Name returns the name of the container, if it's known.
+func (c *Container) Name() string {
+	return c.name
+}
+
+func (c *Container) String() string {
+	if len(c.name) > 0 {
+		return c.name
+	}
+	return c.id
+}
+
+// Inspect returns details about the container's current state.
+func (c *Container) Inspect(ctx context.Context) (*ContainerInfo, error) {
+	return c.d.InspectContainer(ctx, c.id)
+}
+
+// Logs creates a [DockerLogs] for the container.
+func (c *Container) Logs() *DockerLogs {
+	return c.d.Logs(c.id)
+}
+
+// Exec creates a [DockerExec] that will run a command in the container.
+func (c *Container) Exec(cmd string, args ...string) *DockerExec {
+	return c.d.Exec(c.id, cmd, args...)
+}
+
+// Stop creates a [DockerStop] for the container.
+func (c *Container) Stop() *DockerStop {
+	return c.d.Stop(c.id)
+}
+
+// Kill creates a [DockerKill] for the container.
+func (c *Container) Kill() *DockerKill {
+	return c.d.Kill(c.id)
+}
+
+// Restart creates a [DockerRestart] for the container.
+func (c *Container) Restart() *DockerRestart {
+	return c.d.Restart(c.id)
+}
+
+// Pause will suspend all processes in the container.
+func (c *Container) Pause() Task {
+	return c.d.Pause(c.id)
+}
+
+// Unpause will resume all processes in the container.
+func (c *Container) Unpause() Task {
+	return c.d.Unpause(c.id)
+}
+
+// Rename will change the name of the container, updating the handle if successful.
+func (c *Container) Rename(newName string) Task {
+	rename := c.d.Rename(c.id, newName)
+	return func(ctx context.Context) error {
+		if err := rename.Run(ctx); err != nil {
+			return err
+		}
+		c.name = newName
+		return nil
+	}
+}
+
+// Update creates a [DockerUpdate] for the container.
+func (c *Container) Update() *DockerUpdate {
+	return c.d.Update(c.id)
+}
+
+// Wait blocks until the container exits, and returns its exit code.
+func (c *Container) Wait(ctx context.Context) (int, error) {
+	return c.d.Wait(ctx, c.id)
+}
+
+// Remove creates a [DockerRemoveContainer] for the container.
+func (c *Container) Remove() *DockerRemoveContainer {
+	return c.d.RemoveContainer(c.id)
+}
+
+// Port returns the host port published for the given TCP container port.
+// Use [Container.ProtocolPort] for other protocols.
+func (c *Container) Port(ctx context.Context, containerPort int) (int, error) {
+	if containerPort < 1 {
+		return 0, fmt.Errorf("invalid port value '%d'", containerPort)
+	}
+	_, port, err := c.d.hostBinding(ctx, c.id, strconv.Itoa(containerPort))
+	return port, err
+}
+
+// CopyTo creates a [DockerCopy] that copies a file or directory from the host into the container.
+func (c *Container) CopyTo(hostPath, containerPath PathString) *DockerCopy {
+	return c.d.CopyTo(c.id, hostPath, containerPath)
+}
+
+// CopyFrom creates a [DockerCopy] that copies a file or directory from the container to the host.
+func (c *Container) CopyFrom(containerPath, hostPath PathString) *DockerCopy {
+	return c.d.CopyFrom(c.id, containerPath, hostPath)
+}
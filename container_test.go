@@ -0,0 +1,35 @@
+package modmake_docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRun_Start(t *testing.T) {
+	d := Docker().Dry()
+	c, err := d.Run("some-image:latest").Name("some-container").Start(context.Background())
+	assert.Nil(t, c)
+	var dryRun *DryRunResult
+	assert.True(t, errors.As(err, &dryRun), "Should have been a dry run error")
+	assert.Equal(t, "dry run: docker run --name=some-container -d some-image:latest", err.Error())
+}
+
+func TestContainer(t *testing.T) {
+	d := Docker().Dry()
+	c := d.Container("some-container")
+	isDryRunResult(t, c.Logs().Task(), "docker logs some-container")
+	isDryRunResult(t, c.Exec("echo", "Hello!").Task(), "docker exec -i some-container echo Hello!")
+	isDryRunResult(t, c.Stop().Task(), "docker stop some-container")
+	isDryRunResult(t, c.Kill().Task(), "docker kill some-container")
+	isDryRunResult(t, c.Remove().Force().Task(), "docker rm -f some-container")
+	isDryRunResult(t, c.CopyTo("./build/app", "/app/bin").Task(), "docker cp ./build/app some-container:/app/bin")
+	isDryRunResult(t, c.CopyFrom("/app/bin", "./build/app").Task(), "docker cp some-container:/app/bin ./build/app")
+}
+
+func TestContainer_Wait(t *testing.T) {
+	_, err := Docker().Dry().Container("some-container").Wait(context.Background())
+	assert.Equal(t, "dry run: docker wait some-container", err.Error())
+}
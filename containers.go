@@ -0,0 +1,230 @@
+package modmake_docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// ContainerStatus is used with [DockerContainers.Status] to filter containers by their current status.
+type ContainerStatus string
+
+const (
+	StatusCreated    ContainerStatus = "created"
+	StatusRestarting ContainerStatus = "restarting"
+	StatusRunning    ContainerStatus = "running"
+	StatusRemoving   ContainerStatus = "removing"
+	StatusPaused     ContainerStatus = "paused"
+	StatusExited     ContainerStatus = "exited"
+	StatusDead       ContainerStatus = "dead"
+)
+
+var knownContainerStatuses = map[ContainerStatus]struct{}{
+	StatusCreated:    {},
+	StatusRestarting: {},
+	StatusRunning:    {},
+	StatusRemoving:   {},
+	StatusPaused:     {},
+	StatusExited:     {},
+	StatusDead:       {},
+}
+
+// Containers creates a [DockerContainers], used to list and act on containers matching a set of filters.
+// By default, only running containers are matched.
+func (d *DockerRef) Containers() *DockerContainers {
+	return &DockerContainers{d: d}
+}
+
+// DockerContainers encapsulates a "docker ps" command, and bulk operations on its results.
+type DockerContainers struct {
+	d       *DockerRef
+	all     bool
+	filters []string
+}
+
+// ContainerSummary is a container listed by [DockerContainers.List].
+type ContainerSummary struct {
+	ID         string `json:"ID"`
+	Names      string `json:"Names"`
+	Image      string `json:"Image"`
+	Command    string `json:"Command"`
+	CreatedAt  string `json:"CreatedAt"`
+	RunningFor string `json:"RunningFor"`
+	State      string `json:"State"`
+	Status     string `json:"Status"`
+	Ports      string `json:"Ports"`
+	Networks   string `json:"Networks"`
+	Mounts     string `json:"Mounts"`
+	Labels     string `json:"Labels"`
+	Size       string `json:"Size"`
+}
+
+// LabelMap parses the comma separated Labels string into a map.
+func (s *ContainerSummary) LabelMap() map[string]string {
+	return parseLabelList(s.Labels)
+}
+
+// All will match containers in any state, not just running containers.
+func (c *DockerContainers) All() *DockerContainers {
+	c.all = true
+	return c
+}
+
+// Label matches containers with the given label key, and value if it's not empty.
+func (c *DockerContainers) Label(key, val string) *DockerContainers {
+	anyBlankPanic(strmap{"key": &key})
+	if len(val) > 0 {
+		return c.filter("label", key+"="+val)
+	}
+	return c.filter("label", key)
+}
+
+// Name matches containers with a name containing the given string.
+func (c *DockerContainers) Name(name string) *DockerContainers {
+	anyBlankPanic(strmap{"name": &name})
+	return c.filter("name", name)
+}
+
+// Status matches containers with the given [ContainerStatus].
+// This implies [DockerContainers.All], since only running containers would be matched otherwise.
+func (c *DockerContainers) Status(status ContainerStatus) *DockerContainers {
+	if _, ok := knownContainerStatuses[status]; !ok {
+		panicf("unknown container status '%s'", status)
+		return c
+	}
+	c.all = true
+	return c.filter("status", string(status))
+}
+
+// Ancestor matches containers created from the given image, or an image descending from it.
+func (c *DockerContainers) Ancestor(image string) *DockerContainers {
+	anyBlankPanic(strmap{"image": &image})
+	return c.filter("ancestor", image)
+}
+
+// Network matches containers connected to the given network.
+func (c *DockerContainers) Network(network string) *DockerContainers {
+	anyBlankPanic(strmap{"network": &network})
+	return c.filter("network", network)
+}
+
+func (c *DockerContainers) filter(key, val string) *DockerContainers {
+	c.filters = append(c.filters, key+"="+val)
+	return c
+}
+
+func (c *DockerContainers) args() []string {
+	args := []string{"ps"}
+	if c.all {
+		args = append(args, "-a")
+	}
+	args = append(args, "--no-trunc")
+	for _, filter := range c.filters {
+		args = append(args, "--filter", filter)
+	}
+	// A template is used instead of "json" to support older CLI versions.
+	return append(args, "--format={{json .}}")
+}
+
+// List returns a summary of all matching containers.
+func (c *DockerContainers) List(ctx context.Context) ([]*ContainerSummary, error) {
+	out, err := c.d.output(ctx, c.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONLines[ContainerSummary](out)
+}
+
+// IDs returns the IDs of all matching containers.
+func (c *DockerContainers) IDs(ctx context.Context) ([]string, error) {
+	containers, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(containers))
+	for i, container := range containers {
+		ids[i] = container.ID
+	}
+	return ids, nil
+}
+
+// Stop creates a Task that stops all matching containers.
+func (c *DockerContainers) Stop() Task {
+	return func(ctx context.Context) error {
+		ids, err := c.IDs(ctx)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		return c.d.Command(append([]string{"stop"}, ids...)...).Run(ctx)
+	}
+}
+
+// Remove creates a [DockerRemoveContainers] that removes all matching containers.
+func (c *DockerContainers) Remove() *DockerRemoveContainers {
+	return &DockerRemoveContainers{list: c}
+}
+
+// DockerRemoveContainers removes every container matched by [DockerContainers], with the same semantics as [DockerRemoveContainer].
+type DockerRemoveContainers struct {
+	list  *DockerContainers
+	force bool
+}
+
+// Force will force removal of the matching containers, including running containers.
+func (r *DockerRemoveContainers) Force() *DockerRemoveContainers {
+	r.force = true
+	return r
+}
+
+func (r *DockerRemoveContainers) Task() Task {
+	return r.Run
+}
+
+func (r *DockerRemoveContainers) Run(ctx context.Context) error {
+	ids, err := r.list.IDs(ctx)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	rm := &DockerRemoveContainer{d: r.list.d, names: ids, force: r.force}
+	return rm.Run(ctx)
+}
+
+// decodeJSONLines decodes output with a JSON object on each line, as produced by list commands using the "{{json .}}" format.
+func decodeJSONLines[T any](out string) ([]*T, error) {
+	var results []*T
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+		result := new(T)
+		if err := json.Unmarshal([]byte(line), result); err != nil {
+			return nil, fmt.Errorf("failed to decode list output: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read list output: %w", err)
+	}
+	return results, nil
+}
+
+// parseLabelList parses a comma separated list of key=value labels, as reported by list commands.
+func parseLabelList(labels string) map[string]string {
+	result := map[string]string{}
+	for _, label := range strings.Split(labels, ",") {
+		label = strings.TrimSpace(label)
+		if len(label) == 0 {
+			continue
+		}
+		key, val, _ := strings.Cut(label, "=")
+		result[key] = val
+	}
+	return result
+}
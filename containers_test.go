@@ -0,0 +1,60 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerContainers_List(t *testing.T) {
+	ctx := context.Background()
+	d := Docker().Dry()
+	_, err := d.Containers().List(ctx)
+	assert.Equal(t, "dry run: docker ps --no-trunc --format={{json .}}", err.Error())
+
+	_, err = d.Containers().
+		All().
+		Label("com.example.build", "").
+		Label("com.example.step", "test").
+		Name("db").
+		Ancestor("postgres:16").
+		Network("somenet").
+		List(ctx)
+	assert.Equal(t, "dry run: docker ps -a --no-trunc --filter label=com.example.build --filter label=com.example.step=test "+
+		"--filter name=db --filter ancestor=postgres:16 --filter network=somenet --format={{json .}}", err.Error())
+}
+
+func TestDockerContainers_Status(t *testing.T) {
+	_, err := Docker().Dry().Containers().Status(StatusExited).List(context.Background())
+	assert.Equal(t, "dry run: docker ps -a --no-trunc --filter status=exited --format={{json .}}", err.Error())
+	assert.Panics(t, func() {
+		Docker().Dry().Containers().Status("sleeping")
+	})
+}
+
+func TestDockerContainers_Bulk(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Containers().Label("build", "1").Stop(),
+		"docker ps --no-trunc --filter label=build=1 --format={{json .}}")
+	isDryRunResult(t, d.Containers().Label("build", "1").Remove().Force().Task(),
+		"docker ps --no-trunc --filter label=build=1 --format={{json .}}")
+}
+
+func TestDecodeJSONLines_Containers(t *testing.T) {
+	out := `{"Command":"\"docker-entrypoint.s…\"","CreatedAt":"2024-03-01 12:00:00 +0000 UTC","ID":"f2b1c4e9d7a3","Image":"postgres:16","Labels":"com.example.build=123,maintainer=someone","Names":"db","Networks":"somenet","Ports":"0.0.0.0:49153->5432/tcp","State":"running","Status":"Up 5 minutes"}
+{"ID":"a1b2c3","Image":"redis:7","Names":"cache","State":"exited","Labels":""}
+`
+	containers, err := decodeJSONLines[ContainerSummary](out)
+	assert.NoError(t, err)
+	assert.Len(t, containers, 2)
+	assert.Equal(t, "f2b1c4e9d7a3", containers[0].ID)
+	assert.Equal(t, "running", containers[0].State)
+	assert.Equal(t, map[string]string{"com.example.build": "123", "maintainer": "someone"}, containers[0].LabelMap())
+	assert.Equal(t, "cache", containers[1].Names)
+	assert.Empty(t, containers[1].LabelMap())
+
+	containers, err = decodeJSONLines[ContainerSummary]("")
+	assert.NoError(t, err)
+	assert.Empty(t, containers)
+}
@@ -0,0 +1,147 @@
+package modmake_docker
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// CopyTo creates a [DockerCopy] that copies a file or directory from the host into a container.
+// The container may be stopped, but must exist.
+func (d *DockerRef) CopyTo(container string, hostPath, containerPath PathString) *DockerCopy {
+	anyBlankPanic(strmap{"container": &container})
+	src, dst := hostPath.String(), containerPath.ToSlash()
+	anyBlankPanic(strmap{"hostPath": &src, "containerPath": &dst})
+	return &DockerCopy{d: d, src: src, dst: container + ":" + dst}
+}
+
+// CopyFrom creates a [DockerCopy] that copies a file or directory from a container to the host.
+// The container may be stopped, but must exist.
+func (d *DockerRef) CopyFrom(container string, containerPath, hostPath PathString) *DockerCopy {
+	anyBlankPanic(strmap{"container": &container})
+	src, dst := containerPath.ToSlash(), hostPath.String()
+	anyBlankPanic(strmap{"containerPath": &src, "hostPath": &dst})
+	return &DockerCopy{d: d, src: container + ":" + src, dst: dst}
+}
+
+// DockerCopy encapsulates a "docker cp" command.
+type DockerCopy struct {
+	d          *DockerRef
+	src, dst   string
+	archive    bool
+	followLink bool
+}
+
+// Archive will preserve the UID/GID of copied files.
+func (c *DockerCopy) Archive() *DockerCopy {
+	c.archive = true
+	return c
+}
+
+// FollowLink will copy the target of a symbolic link source path, rather than the link itself.
+func (c *DockerCopy) FollowLink() *DockerCopy {
+	c.followLink = true
+	return c
+}
+
+func (c *DockerCopy) Task() Task {
+	return c.Run
+}
+
+func (c *DockerCopy) Run(ctx context.Context) error {
+	args := []string{"cp"}
+	if c.archive {
+		args = append(args, "-a")
+	}
+	if c.followLink {
+		args = append(args, "-L")
+	}
+	return c.d.Command(append(args, c.src, c.dst)...).Run(ctx)
+}
+
+// CopyTarTo creates a Task that extracts a tar archive read from archive into a directory in a container.
+func (d *DockerRef) CopyTarTo(container string, containerDir PathString, archive io.Reader) Task {
+	anyBlankPanic(strmap{"container": &container})
+	if archive == nil {
+		panic("nil archive reader")
+	}
+	dst := containerDir.ToSlash()
+	anyBlankPanic(strmap{"containerDir": &dst})
+	return d.commandIO(archive, nil, nil, "cp", "-", container+":"+dst)
+}
+
+// CopyTarFrom creates a Task that writes a tar archive of a file or directory in a container to archive.
+func (d *DockerRef) CopyTarFrom(container string, containerPath PathString, archive io.Writer) Task {
+	anyBlankPanic(strmap{"container": &container})
+	if archive == nil {
+		panic("nil archive writer")
+	}
+	src := containerPath.ToSlash()
+	anyBlankPanic(strmap{"containerPath": &src})
+	return d.commandIO(nil, archive, nil, "cp", container+":"+src, "-")
+}
+
+// WriteTarTo creates a Task that extracts files written by fn into a directory in a container, without creating a temporary archive.
+// The [tar.Writer] is closed after fn returns.
+func (d *DockerRef) WriteTarTo(container string, containerDir PathString, fn func(tw *tar.Writer) error) Task {
+	if fn == nil {
+		panic("nil tar writer function")
+	}
+	if d.dryRun {
+		return d.CopyTarTo(container, containerDir, strings.NewReader(""))
+	}
+	return func(ctx context.Context) error {
+		pr, pw := io.Pipe()
+		written := make(chan error, 1)
+		go func() {
+			tw := tar.NewWriter(pw)
+			err := fn(tw)
+			if err == nil {
+				err = tw.Close()
+			}
+			_ = pw.CloseWithError(err)
+			written <- err
+		}()
+		cmdErr := d.CopyTarTo(container, containerDir, pr).Run(ctx)
+		// Unblock the writer if the command exited early.
+		_ = pr.Close()
+		writeErr := <-written
+		if errors.Is(writeErr, io.ErrClosedPipe) && cmdErr != nil {
+			return cmdErr
+		}
+		return errors.Join(writeErr, cmdErr)
+	}
+}
+
+// ReadTarFrom creates a Task that passes a tar archive of a file or directory in a container to fn, without creating a temporary archive.
+func (d *DockerRef) ReadTarFrom(container string, containerPath PathString, fn func(tr *tar.Reader) error) Task {
+	if fn == nil {
+		panic("nil tar reader function")
+	}
+	if d.dryRun {
+		return d.CopyTarFrom(container, containerPath, io.Discard)
+	}
+	return func(ctx context.Context) error {
+		pr, pw := io.Pipe()
+		copied := make(chan error, 1)
+		go func() {
+			err := d.CopyTarFrom(container, containerPath, pw).Run(ctx)
+			_ = pw.CloseWithError(err)
+			copied <- err
+		}()
+		readErr := fn(tar.NewReader(pr))
+		if readErr != nil {
+			// Stop the command, since nothing else will be read.
+			_ = pr.CloseWithError(readErr)
+			<-copied
+			return readErr
+		}
+		// Drain any trailing data so the command can exit normally.
+		_, _ = io.Copy(io.Discard, pr)
+		return <-copied
+	}
+}
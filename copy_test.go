@@ -0,0 +1,31 @@
+package modmake_docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerCopy(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.CopyTo("some-container", "./build/app", "/app/bin").Task(),
+		"docker cp ./build/app some-container:/app/bin")
+	isDryRunResult(t, d.CopyFrom("some-container", "/app/bin", "./build/app").Archive().FollowLink().Task(),
+		"docker cp -a -L some-container:/app/bin ./build/app")
+	assert.Panics(t, func() { d.CopyTo("some-container", "", "/app/bin") })
+}
+
+func TestDockerCopy_Tar(t *testing.T) {
+	d := Docker().Dry()
+	var buf bytes.Buffer
+	isDryRunResult(t, d.CopyTarTo("some-container", "/app", &buf), "docker cp - some-container:/app")
+	isDryRunResult(t, d.CopyTarFrom("some-container", "/app/bin", &buf), "docker cp some-container:/app/bin -")
+	isDryRunResult(t, d.WriteTarTo("some-container", "/app", func(tw *tar.Writer) error {
+		return nil
+	}), "docker cp - some-container:/app")
+	isDryRunResult(t, d.ReadTarFrom("some-container", "/app/bin", func(tr *tar.Reader) error {
+		return nil
+	}), "docker cp some-container:/app/bin -")
+}
@@ -1,12 +1,17 @@
 package modmake_docker
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	. "github.com/saylorsolutions/modmake"
+	"io"
+	"os"
 	"os/exec"
+	"regexp"
 	"strings"
+
+	. "github.com/saylorsolutions/modmake"
 )
 
 var (
@@ -60,13 +65,22 @@ func (d *DockerRef) Command(args ...string) Task {
 	if d.dryRun {
 		return dryRunExec(args...)
 	}
+	cmdArgs, err := d.commandLine(args...)
+	if err != nil {
+		return Error("%w", err)
+	}
+	return Exec(cmdArgs...).CaptureStdin().Run
+}
+
+// commandLine locates the Docker CLI if needed, and returns the full command line used to execute the given arguments.
+func (d *DockerRef) commandLine(args ...string) ([]string, error) {
 	if d.exePath == Path("") {
 		_path, err := exec.LookPath("docker")
 		if err != nil {
 			if errors.Is(err, ErrNoDockerFound) {
-				return Error("%w: unable to locate docker, and this is not a dry run", ErrNoDockerFound)
+				return nil, fmt.Errorf("%w: unable to locate docker, and this is not a dry run", ErrNoDockerFound)
 			}
-			return Error("unexpected error: %w", err)
+			return nil, fmt.Errorf("unexpected error: %w", err)
 		}
 		d.exePath = Path(_path)
 	}
@@ -75,5 +89,78 @@ func (d *DockerRef) Command(args ...string) Task {
 	if len(sudoPrefix) > 0 {
 		cmdArgs = append([]string{sudoPrefix}, cmdArgs...)
 	}
-	return Exec(cmdArgs...).CaptureStdin().Run
+	return cmdArgs, nil
+}
+
+// commandIO works like [DockerRef.Command], but attaches the given streams to the Docker CLI process.
+// A nil stdout or stderr will be replaced with os.Stdout or os.Stderr respectively.
+func (d *DockerRef) commandIO(stdin io.Reader, stdout, stderr io.Writer, args ...string) Task {
+	if d.dryRun {
+		return dryRunExec(args...)
+	}
+	return func(ctx context.Context) error {
+		cmdArgs, err := d.commandLine(args...)
+		if err != nil {
+			return err
+		}
+		if stdout == nil {
+			stdout = os.Stdout
+		}
+		if stderr == nil {
+			stderr = os.Stderr
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			cmd := exec.CommandContext(ctx, cmdArgs[0], cmdArgs[1:]...)
+			cmd.Stdin = stdin
+			cmd.Stdout = stdout
+			cmd.Stderr = stderr
+			return cmd.Run()
+		}
+	}
+}
+
+// output runs a Docker command and returns what it wrote to STDOUT, with surrounding whitespace trimmed.
+// Anything written to STDERR is included in the returned error if the command fails.
+func (d *DockerRef) output(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := d.commandIO(nil, &stdout, &stderr, args...).Run(ctx); err != nil {
+		return "", commandErr(args, err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// objectExists reports whether a network or volume with exactly the given name exists, using a name filter.
+// Unlike inspecting the object, this doesn't depend on the wording of Docker's error messages.
+func (d *DockerRef) objectExists(ctx context.Context, kind, name string) (bool, error) {
+	out, err := d.output(ctx, kind, "ls", "--filter", "name=^"+regexp.QuoteMeta(name)+"$", "-q")
+	if err != nil {
+		return false, err
+	}
+	return len(out) > 0, nil
+}
+
+// imageExists reports whether an image with the given reference exists, like "my-image:1".
+func (d *DockerRef) imageExists(ctx context.Context, ref string) (bool, error) {
+	out, err := d.output(ctx, "image", "ls", "-q", ref)
+	if err != nil {
+		return false, err
+	}
+	return len(out) > 0, nil
+}
+
+// commandErr adds the sub-command and STDERR output to an error from running a Docker command.
+// A [DryRunResult] is returned as-is.
+func commandErr(args []string, err error, stderr string) error {
+	var dryRun *DryRunResult
+	if errors.As(err, &dryRun) {
+		return err
+	}
+	msg := strings.TrimSpace(stderr)
+	if len(msg) > 0 {
+		return fmt.Errorf("docker %s: %w: %s", args[0], err, msg)
+	}
+	return fmt.Errorf("docker %s: %w", args[0], err)
 }
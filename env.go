@@ -0,0 +1,226 @@
+package modmake_docker
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// redactedValue replaces secret values wherever a command line would be shown.
+const redactedValue = "******"
+
+func validEnvKey(key string) string {
+	anyBlankPanic(strmap{"key": &key})
+	key = strings.TrimSpace(key)
+	if strings.ContainsAny(key, "= \t\r\n") {
+		panicf("invalid environment variable name '%s'", key)
+	}
+	return key
+}
+
+// PassEnvVar will pass the named environment variables from the host to the container, without their values appearing on the command line.
+// Variables that aren't set on the host aren't set in the container.
+func (r *DockerRun) PassEnvVar(keys ...string) *DockerRun {
+	for _, key := range keys {
+		r.env = append(r.env, validEnvKey(key))
+	}
+	return r
+}
+
+// SetEnvVars will set each environment variable in vars, in order of their names.
+// This works well with [ReadEnvFile].
+func (r *DockerRun) SetEnvVars(vars map[string]string) *DockerRun {
+	for _, key := range sortedKeys(vars) {
+		r.SetEnvVar(key, vars[key])
+	}
+	return r
+}
+
+// SetSecretEnvVar will set an environment variable in the container, without its value appearing on the command line.
+// The value is written to a temporary env file that only the current user can read, which is removed once the Docker CLI exits.
+// The value is redacted in dry run output.
+// A variable set with [DockerRun.SetEnvVar] takes precedence over a secret with the same name.
+func (r *DockerRun) SetSecretEnvVar(key, val string) *DockerRun {
+	key = validEnvKey(key)
+	if strings.ContainsAny(val, "\r\n") {
+		panicf("secret environment variable '%s' must not contain line breaks", key)
+		return r
+	}
+	r.secretEnv = append(r.secretEnv, key+"="+val)
+	return r
+}
+
+// SetSecretEnvVars will set each environment variable in vars as a secret, as described in [DockerRun.SetSecretEnvVar].
+func (r *DockerRun) SetSecretEnvVars(vars map[string]string) *DockerRun {
+	for _, key := range sortedKeys(vars) {
+		r.SetSecretEnvVar(key, vars[key])
+	}
+	return r
+}
+
+// EnvFile will set environment variables in the container from a file on the host, which is resolved to an absolute path.
+// The file is read by Docker, so it must be in Docker's env file format, with one "KEY=value" per line and no quoting.
+// Variables set with [DockerRun.SetEnvVar] take precedence over those from an env file.
+func (r *DockerRun) EnvFile(path PathString) *DockerRun {
+	absPath, err := path.Abs()
+	if err != nil {
+		panicf("failed to get absolute path for env file: %v", err)
+		return r
+	}
+	r.envFiles = append(r.envFiles, absPath.String())
+	return r
+}
+
+// secretEnvArgs returns the arguments used to pass secret environment variables.
+// When there's no secret env file, as in a dry run, the values are redacted.
+func (r *DockerRun) secretEnvArgs(secretFile string) []string {
+	if len(r.secretEnv) == 0 {
+		return nil
+	}
+	if len(secretFile) > 0 {
+		return []string{"--env-file=" + secretFile}
+	}
+	var args []string
+	for _, env := range r.secretEnv {
+		key, _, _ := strings.Cut(env, "=")
+		args = append(args, "-e", key+"="+redactedValue)
+	}
+	return args
+}
+
+// writeSecretEnv writes secret environment variables to an env file in dir, returning its path.
+func (r *DockerRun) writeSecretEnv(dir string) (string, error) {
+	secretFile := filepath.Join(dir, "secret.env")
+	if err := os.WriteFile(secretFile, []byte(strings.Join(r.secretEnv, "\n")+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write secret env file: %w", err)
+	}
+	return secretFile, nil
+}
+
+func sortedKeys(vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReadEnvFile reads environment variables from one or more ".env" files, as described in [ParseEnv].
+// Variables in later files override those in earlier files, so per-environment configuration can be layered over defaults.
+//
+//	vars, err := ReadEnvFile(".env", Path(".env." + environment))
+func ReadEnvFile(paths ...PathString) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, path := range paths {
+		f, err := os.Open(path.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open env file: %w", err)
+		}
+		parsed, err := ParseEnv(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse env file '%s': %w", path.String(), err)
+		}
+		for key, val := range parsed {
+			vars[key] = val
+		}
+	}
+	return vars, nil
+}
+
+// ParseEnv parses environment variables in the ".env" format.
+// Each line is a "KEY=value" pair, optionally preceded by "export".
+// Blank lines and lines starting with "#" are ignored.
+// Values may be single quoted to be taken literally, or double quoted to allow escapes like "\n".
+// Unquoted values are trimmed, and anything after " #" is treated as a comment.
+// Variables are not expanded.
+func ParseEnv(r io.Reader) (map[string]string, error) {
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || len(key) == 0 || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNum)
+		}
+		val, err := parseEnvValue(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		vars[key] = val
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+func parseEnvValue(val string) (string, error) {
+	if len(val) == 0 {
+		return "", nil
+	}
+	var (
+		buf  strings.Builder
+		rest string
+	)
+	switch val[0] {
+	case '\'':
+		end := strings.IndexByte(val[1:], '\'')
+		if end < 0 {
+			return "", errors.New("unterminated single quoted value")
+		}
+		buf.WriteString(val[1 : end+1])
+		rest = val[end+2:]
+	case '"':
+		i := 1
+		for ; i < len(val) && val[i] != '"'; i++ {
+			if val[i] != '\\' || i+1 == len(val) {
+				buf.WriteByte(val[i])
+				continue
+			}
+			i++
+			switch val[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case '"', '\\', '$':
+				buf.WriteByte(val[i])
+			default:
+				buf.WriteByte('\\')
+				buf.WriteByte(val[i])
+			}
+		}
+		if i >= len(val) {
+			return "", errors.New("unterminated double quoted value")
+		}
+		rest = val[i+1:]
+	default:
+		if idx := strings.Index(val, " #"); idx >= 0 {
+			val = val[:idx]
+		}
+		return strings.TrimSpace(val), nil
+	}
+	rest = strings.TrimSpace(rest)
+	if len(rest) > 0 && !strings.HasPrefix(rest, "#") {
+		return "", errors.New("unexpected characters after quoted value")
+	}
+	return buf.String(), nil
+}
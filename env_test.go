@@ -0,0 +1,92 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/saylorsolutions/modmake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRun_Run_Env(t *testing.T) {
+	envFile, _ := Path("./test.env").Abs()
+	err := Docker().Dry().Run("some-image:latest").
+		EnvFile(envFile).
+		SetEnvVar("EMPTY", "").
+		SetEnvVar(" PADDED ", " value ").
+		PassEnvVar("HOME").
+		SetSecretEnvVar("API_TOKEN", "hunter2").
+		SetEnvVars(map[string]string{"B": "2", "A": "1"}).
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("dry run: docker run --env-file=%s -e API_TOKEN=****** -e EMPTY= -e PADDED= value  -e HOME -e A=1 -e B=2 some-image:latest", envFile.String()), err.Error())
+	assert.NotContains(t, err.Error(), "hunter2")
+}
+
+func TestDockerRun_Env_Invalid(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest")
+	assert.Panics(t, func() { run.SetEnvVar("", "value") })
+	assert.Panics(t, func() { run.SetEnvVar("A=B", "value") })
+	assert.Panics(t, func() { run.PassEnvVar("NOT VALID") })
+	assert.Panics(t, func() { run.SetSecretEnvVar("KEY", "line\nbreak") })
+}
+
+func TestDockerRun_SecretEnvFile(t *testing.T) {
+	run := Docker().Run("some-image:latest").
+		SetSecretEnvVar("API_TOKEN", "hunter2").
+		SetSecretEnvVar("DB_PASSWORD", "p@ss word")
+	dir := t.TempDir()
+	secretFile, err := run.writeSecretEnv(dir)
+	assert.NoError(t, err)
+	data, err := os.ReadFile(secretFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "API_TOKEN=hunter2\nDB_PASSWORD=p@ss word\n", string(data))
+	args := strings.Join(run.runArgs(false, secretFile), " ")
+	assert.Equal(t, "run --env-file="+secretFile+" some-image:latest", args)
+}
+
+func TestParseEnv(t *testing.T) {
+	vars, err := ParseEnv(strings.NewReader(`
+# Comment
+PLAIN=value
+export EXPORTED=yes
+TRIMMED =  spaced out   # comment
+EMPTY=
+SINGLE='literal \n $HOME # not a comment'
+DOUBLE="line\nbreak \"quoted\"" # comment
+HASH=a#b
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"PLAIN":    "value",
+		"EXPORTED": "yes",
+		"TRIMMED":  "spaced out",
+		"EMPTY":    "",
+		"SINGLE":   `literal \n $HOME # not a comment`,
+		"DOUBLE":   "line\nbreak \"quoted\"",
+		"HASH":     "a#b",
+	}, vars)
+
+	for _, input := range []string{"NO_EQUALS", "=value", "BAD KEY=value", `OPEN="value`, "OPEN='value", `EXTRA="value" extra`} {
+		_, err := ParseEnv(strings.NewReader(input))
+		assert.Error(t, err, "input '%s' should be invalid", input)
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".env")
+	ci := filepath.Join(dir, ".env.ci")
+	assert.NoError(t, os.WriteFile(base, []byte("A=1\nB=2\n"), 0600))
+	assert.NoError(t, os.WriteFile(ci, []byte("B=ci\nC=3\n"), 0600))
+	vars, err := ReadEnvFile(Path(base), Path(ci))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"A": "1", "B": "ci", "C": "3"}, vars)
+
+	_, err = ReadEnvFile(Path(filepath.Join(dir, ".env.missing")))
+	assert.Error(t, err)
+}
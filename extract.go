@@ -0,0 +1,64 @@
+package modmake_docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// Extract creates a [DockerExtract], used to copy files out of an image without running it.
+// This is useful to get build outputs from an image produced by [DockerBuild].
+func (d *DockerRef) Extract(image string) *DockerExtract {
+	anyBlankPanic(strmap{"image": &image})
+	return &DockerExtract{d: d, image: image}
+}
+
+// DockerExtract creates a stopped container from an image, copies paths out of it, and always removes the container afterward.
+type DockerExtract struct {
+	d     *DockerRef
+	image string
+	paths []extractPath
+}
+
+type extractPath struct {
+	containerPath PathString
+	hostPath      PathString
+}
+
+// Path adds a file or directory in the image to be copied to the host.
+func (e *DockerExtract) Path(containerPath, hostPath PathString) *DockerExtract {
+	cps, hps := containerPath.ToSlash(), hostPath.String()
+	anyBlankPanic(strmap{"containerPath": &cps, "hostPath": &hps})
+	e.paths = append(e.paths, extractPath{containerPath: containerPath, hostPath: hostPath})
+	return e
+}
+
+func (e *DockerExtract) Task() Task {
+	return e.Run
+}
+
+func (e *DockerExtract) Run(ctx context.Context) (err error) {
+	if len(e.paths) == 0 {
+		return errors.New("no paths to extract")
+	}
+	// The command is never run, but is required to create a container from images without a default command.
+	id, err := e.d.output(ctx, "create", e.image, "modmake-extract")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), defaultCleanupTimeout)
+		defer cancel()
+		if _, rmErr := e.d.output(cleanupCtx, "rm", "-f", id); rmErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to remove extract container '%s': %w", id, rmErr))
+		}
+	}()
+	for _, p := range e.paths {
+		if err := e.d.CopyFrom(id, p.containerPath, p.hostPath).Run(ctx); err != nil {
+			return fmt.Errorf("failed to extract '%s' from image '%s': %w", p.containerPath.ToSlash(), e.image, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerExtract(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Extract("my-builder:latest").Path("/app/bin/my-app", "./build/my-app").Task(),
+		"docker create my-builder:latest modmake-extract")
+}
+
+func TestDockerExtract_NoPaths(t *testing.T) {
+	err := Docker().Dry().Extract("my-builder:latest").Run(context.Background())
+	assert.EqualError(t, err, "no paths to extract")
+}
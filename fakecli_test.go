@@ -0,0 +1,34 @@
+//go:build !windows
+
+package modmake_docker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// fakeDocker creates a [DockerRef] that runs a shell script in place of the Docker CLI.
+// Each command line the script is called with is recorded, and returned by the calls function.
+func fakeDocker(t *testing.T, script string) (d *DockerRef, calls func() []string) {
+	t.Helper()
+	groupsOnce.Do(func() {})
+	addSudo = false
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "calls")
+	exe := filepath.Join(dir, "docker")
+	script = "#!/bin/sh\necho \"$@\" >> '" + logFile + "'\n" + script + "\n"
+	if err := os.WriteFile(exe, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return &DockerRef{exePath: Path(exe)}, func() []string {
+		data, err := os.ReadFile(logFile)
+		if err != nil {
+			return nil
+		}
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
@@ -0,0 +1,8 @@
+package modmake_docker
+
+import "testing"
+
+func fakeDocker(t *testing.T, _ string) (*DockerRef, func() []string) {
+	t.Skip("the fake Docker CLI requires a POSIX shell")
+	return nil, nil
+}
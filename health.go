@@ -0,0 +1,110 @@
+package modmake_docker
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HealthCheck sets a command that Docker runs in the container to check its health, replacing any health check defined by the image.
+// The command is healthy if it exits with code 0.
+// Each argument is quoted, so it's passed to the command as-is.
+// Use [WaitForHealthy] to wait for the container to become healthy.
+func (r *DockerRun) HealthCheck(cmd string, args ...string) *DockerRun {
+	anyBlankPanic(strmap{"cmd": &cmd})
+	words := []string{shellQuote(cmd)}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return r.HealthCheckShell(strings.Join(words, " "))
+}
+
+// HealthCheckShell sets a shell command that Docker runs in the container to check its health, like "curl -f http://localhost || exit 1".
+// This requires a shell in the container, as with [DockerRun.HealthCheck].
+func (r *DockerRun) HealthCheckShell(script string) *DockerRun {
+	anyBlankPanic(strmap{"script": &script})
+	if r.noHealthCheck {
+		panic("a health check cannot be set when health checks are disabled")
+	}
+	r.healthCmd = script
+	return r
+}
+
+// HealthInterval sets how long to wait between health checks.
+func (r *DockerRun) HealthInterval(interval time.Duration) *DockerRun {
+	r.setHealthDuration("interval", interval)
+	return r
+}
+
+// HealthTimeout sets how long a health check may run before it's considered failed.
+func (r *DockerRun) HealthTimeout(timeout time.Duration) *DockerRun {
+	r.setHealthDuration("timeout", timeout)
+	return r
+}
+
+// HealthStartPeriod sets how long the container has to start before failed health checks count towards the retries.
+func (r *DockerRun) HealthStartPeriod(period time.Duration) *DockerRun {
+	r.setHealthDuration("start-period", period)
+	return r
+}
+
+// HealthRetries sets how many consecutive health checks must fail before the container is considered unhealthy.
+func (r *DockerRun) HealthRetries(retries int) *DockerRun {
+	if retries < 1 {
+		panicf("invalid health retries '%d'", retries)
+		return r
+	}
+	r.setHealthOpt("retries", strconv.Itoa(retries))
+	return r
+}
+
+// NoHealthCheck disables any health check defined by the image.
+func (r *DockerRun) NoHealthCheck() *DockerRun {
+	if len(r.healthCmd) > 0 || len(r.healthOpts) > 0 {
+		panic("health checks cannot be disabled when a health check is configured")
+	}
+	r.noHealthCheck = true
+	return r
+}
+
+func (r *DockerRun) setHealthDuration(name string, d time.Duration) {
+	if d < time.Millisecond {
+		panicf("invalid health %s '%s'", name, d)
+		return
+	}
+	r.setHealthOpt(name, d.String())
+}
+
+// setHealthOpt sets a "--health-*" flag, replacing any previous value.
+func (r *DockerRun) setHealthOpt(name, val string) {
+	if r.noHealthCheck {
+		panic("health check options cannot be set when health checks are disabled")
+	}
+	prefix := "--health-" + name + "="
+	for i, opt := range r.healthOpts {
+		if strings.HasPrefix(opt, prefix) {
+			r.healthOpts[i] = prefix + val
+			return
+		}
+	}
+	r.healthOpts = append(r.healthOpts, prefix+val)
+}
+
+func (r *DockerRun) healthArgs() []string {
+	if r.noHealthCheck {
+		return []string{"--no-healthcheck"}
+	}
+	var args []string
+	if len(r.healthCmd) > 0 {
+		args = append(args, "--health-cmd", r.healthCmd)
+	}
+	return append(args, r.healthOpts...)
+}
+
+// shellQuote quotes a word for a POSIX shell, if needed.
+func shellQuote(word string) string {
+	if len(word) > 0 && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=,@%+") == "" {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
@@ -0,0 +1,51 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRun_Run_HealthCheck(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest").
+		HealthCheck("pg_isready", "-U", "postgres").
+		HealthInterval(time.Second).
+		HealthTimeout(5 * time.Second).
+		HealthRetries(3).
+		HealthStartPeriod(30 * time.Second).
+		HealthInterval(2 * time.Second)
+	assert.Equal(t, []string{"--health-cmd", "pg_isready -U postgres", "--health-interval=2s", "--health-timeout=5s", "--health-retries=3", "--health-start-period=30s"}, run.healthArgs())
+	err := run.Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --health-cmd pg_isready -U postgres --health-interval=2s --health-timeout=5s --health-retries=3 --health-start-period=30s some-image:latest", err.Error())
+
+	run = Docker().Dry().Run("some-image:latest").HealthCheckShell("curl -f http://localhost:8080/health || exit 1")
+	assert.Equal(t, []string{"--health-cmd", "curl -f http://localhost:8080/health || exit 1"}, run.healthArgs())
+}
+
+func TestDockerRun_Run_NoHealthCheck(t *testing.T) {
+	err := Docker().Dry().Run("some-image:latest").NoHealthCheck().Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --no-healthcheck some-image:latest", err.Error())
+}
+
+func TestDockerRun_HealthCheck_Invalid(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest")
+	assert.Panics(t, func() { run.HealthCheck("") })
+	assert.Panics(t, func() { run.HealthInterval(0) })
+	assert.Panics(t, func() { run.HealthRetries(0) })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").HealthRetries(3).NoHealthCheck() })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").NoHealthCheck().HealthCheck("true") })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").NoHealthCheck().HealthTimeout(time.Second) })
+}
+
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "plain-word_1.0", shellQuote("plain-word_1.0"))
+	assert.Equal(t, "http://localhost:8080/health", shellQuote("http://localhost:8080/health"))
+	assert.Equal(t, "''", shellQuote(""))
+	assert.Equal(t, "'two words'", shellQuote("two words"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+	assert.Equal(t, "'$HOME'", shellQuote("$HOME"))
+}
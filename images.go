@@ -0,0 +1,274 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// Images creates a [DockerImages], used to list images matching a set of filters.
+// By default, intermediate images are excluded.
+func (d *DockerRef) Images() *DockerImages {
+	return &DockerImages{d: d}
+}
+
+// DockerImages encapsulates a "docker images" command.
+type DockerImages struct {
+	d       *DockerRef
+	all     bool
+	filters []string
+}
+
+// ImageSummary is an image listed by [DockerImages.List].
+type ImageSummary struct {
+	ID           string `json:"ID"`
+	Repository   string `json:"Repository"`
+	Tag          string `json:"Tag"`
+	Digest       string `json:"Digest"`
+	CreatedAt    string `json:"CreatedAt"`
+	CreatedSince string `json:"CreatedSince"`
+	Size         string `json:"Size"`
+	Containers   string `json:"Containers"`
+}
+
+// All will include intermediate images.
+func (i *DockerImages) All() *DockerImages {
+	i.all = true
+	return i
+}
+
+// Reference matches images with a reference matching the given pattern, like "my-image:*".
+func (i *DockerImages) Reference(pattern string) *DockerImages {
+	anyBlankPanic(strmap{"pattern": &pattern})
+	return i.filter("reference", pattern)
+}
+
+// Label matches images with the given label key, and value if it's not empty.
+func (i *DockerImages) Label(key, val string) *DockerImages {
+	anyBlankPanic(strmap{"key": &key})
+	if len(val) > 0 {
+		return i.filter("label", key+"="+val)
+	}
+	return i.filter("label", key)
+}
+
+// Dangling matches only untagged images if dangling is true, or only tagged images if false.
+func (i *DockerImages) Dangling(dangling bool) *DockerImages {
+	return i.filter("dangling", strconv.FormatBool(dangling))
+}
+
+// Before matches images created before the given image reference.
+func (i *DockerImages) Before(image string) *DockerImages {
+	anyBlankPanic(strmap{"image": &image})
+	return i.filter("before", image)
+}
+
+// Since matches images created after the given image reference.
+func (i *DockerImages) Since(image string) *DockerImages {
+	anyBlankPanic(strmap{"image": &image})
+	return i.filter("since", image)
+}
+
+func (i *DockerImages) filter(key, val string) *DockerImages {
+	i.filters = append(i.filters, key+"="+val)
+	return i
+}
+
+func (i *DockerImages) args() []string {
+	args := []string{"images"}
+	if i.all {
+		args = append(args, "-a")
+	}
+	args = append(args, "--no-trunc")
+	for _, filter := range i.filters {
+		args = append(args, "--filter", filter)
+	}
+	return append(args, "--format={{json .}}")
+}
+
+// List returns a summary of all matching images.
+func (i *DockerImages) List(ctx context.Context) ([]*ImageSummary, error) {
+	out, err := i.d.output(ctx, i.args()...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONLines[ImageSummary](out)
+}
+
+// ImageLayer is an entry in an image's history, as returned by [DockerRef.ImageHistory].
+type ImageLayer struct {
+	// ID is the ID of the image created by this layer, or "<missing>" if it was built elsewhere.
+	ID        string
+	CreatedAt time.Time
+	CreatedBy string
+	Size      int64
+	Comment   string
+}
+
+type rawImageLayer struct {
+	ID        string `json:"ID"`
+	CreatedAt string `json:"CreatedAt"`
+	CreatedBy string `json:"CreatedBy"`
+	Size      string `json:"Size"`
+	Comment   string `json:"Comment"`
+}
+
+// ImageHistory returns the layers of an image, newest first.
+func (d *DockerRef) ImageHistory(ctx context.Context, image string) ([]*ImageLayer, error) {
+	anyBlankPanic(strmap{"image": &image})
+	out, err := d.output(ctx, "history", "--no-trunc", "--human=false", "--format={{json .}}", image)
+	if err != nil {
+		return nil, err
+	}
+	return decodeHistory(out)
+}
+
+func decodeHistory(out string) ([]*ImageLayer, error) {
+	raw, err := decodeJSONLines[rawImageLayer](out)
+	if err != nil {
+		return nil, err
+	}
+	layers := make([]*ImageLayer, len(raw))
+	for i, r := range raw {
+		layer := &ImageLayer{
+			ID:        r.ID,
+			CreatedBy: r.CreatedBy,
+			Comment:   r.Comment,
+		}
+		if len(r.CreatedAt) > 0 {
+			layer.CreatedAt, err = time.Parse(time.RFC3339, r.CreatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse layer creation time '%s': %w", r.CreatedAt, err)
+			}
+		}
+		layer.Size, err = strconv.ParseInt(r.Size, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse layer size '%s': %w", r.Size, err)
+		}
+		layers[i] = layer
+	}
+	return layers, nil
+}
+
+// CompareImageSize creates a [DockerImageSize], which reports the size of an image compared to a previous image, like a prior tag of the same repository.
+func (d *DockerRef) CompareImageSize(image, previous string) *DockerImageSize {
+	anyBlankPanic(strmap{"image": &image, "previous": &previous})
+	return &DockerImageSize{d: d, image: image, previous: previous, maxGrowth: -1, maxGrowthPct: -1}
+}
+
+// DockerImageSize reports image size growth, and can fail a build if an image grows by too much.
+// If the previous image doesn't exist, then only the size of the current image is reported.
+// The report is written to STDOUT, unless [DockerImageSize.Output] is used.
+type DockerImageSize struct {
+	d            *DockerRef
+	image        string
+	previous     string
+	maxGrowth    int64
+	maxGrowthPct float64
+	out          io.Writer
+}
+
+// Output will write the size report to w.
+func (s *DockerImageSize) Output(w io.Writer) *DockerImageSize {
+	if w == nil {
+		panic("nil writer")
+	}
+	s.out = w
+	return s
+}
+
+// MaxGrowth will fail the Task if the image is more than maxBytes larger than the previous image.
+func (s *DockerImageSize) MaxGrowth(maxBytes int64) *DockerImageSize {
+	if maxBytes < 0 {
+		panicf("invalid max growth '%d'", maxBytes)
+		return s
+	}
+	s.maxGrowth = maxBytes
+	return s
+}
+
+// MaxGrowthPercent will fail the Task if the image is more than pct percent larger than the previous image.
+func (s *DockerImageSize) MaxGrowthPercent(pct float64) *DockerImageSize {
+	if pct < 0 {
+		panicf("invalid max growth percent '%f'", pct)
+		return s
+	}
+	s.maxGrowthPct = pct
+	return s
+}
+
+func (s *DockerImageSize) Task() Task {
+	return s.Run
+}
+
+func (s *DockerImageSize) Run(ctx context.Context) error {
+	current, err := s.d.InspectImage(ctx, s.image)
+	if err != nil {
+		return err
+	}
+	exists, err := s.d.imageExists(ctx, s.previous)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		_, err := fmt.Fprintf(s.output(), "Image '%s' is %s, and there is no previous image '%s' to compare to\n", s.image, formatBytes(current.Size), s.previous)
+		return err
+	}
+	previous, err := s.d.InspectImage(ctx, s.previous)
+	if err != nil {
+		return err
+	}
+	return s.compare(current.Size, previous.Size)
+}
+
+func (s *DockerImageSize) compare(current, previous int64) error {
+	growth := current - previous
+	var growthPct float64
+	if previous > 0 {
+		growthPct = float64(growth) / float64(previous) * 100
+	}
+	if _, err := fmt.Fprintf(s.output(), "Image '%s' is %s, which is %s (%+.1f%%) compared to '%s'\n",
+		s.image, formatBytes(current), formatSignedBytes(growth), growthPct, s.previous); err != nil {
+		return err
+	}
+	if s.maxGrowth >= 0 && growth > s.maxGrowth {
+		return fmt.Errorf("image '%s' grew by %s, which is more than the maximum of %s", s.image, formatBytes(growth), formatBytes(s.maxGrowth))
+	}
+	if s.maxGrowthPct >= 0 && growthPct > s.maxGrowthPct {
+		return fmt.Errorf("image '%s' grew by %.1f%%, which is more than the maximum of %.1f%%", s.image, growthPct, s.maxGrowthPct)
+	}
+	return nil
+}
+
+func (s *DockerImageSize) output() io.Writer {
+	if s.out == nil {
+		return os.Stdout
+	}
+	return s.out
+}
+
+// formatBytes formats a byte count with decimal units, like the Docker CLI.
+func formatBytes(size int64) string {
+	units := []string{"B", "kB", "MB", "GB", "TB"}
+	val := float64(size)
+	i := 0
+	for ; val >= 1000 && i < len(units)-1; i++ {
+		val /= 1000
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d%s", size, units[0])
+	}
+	return fmt.Sprintf("%.3g%s", val, units[i])
+}
+
+func formatSignedBytes(size int64) string {
+	if size < 0 {
+		return "-" + formatBytes(-size)
+	}
+	return "+" + formatBytes(size)
+}
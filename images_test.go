@@ -0,0 +1,74 @@
+package modmake_docker
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerImages_List(t *testing.T) {
+	ctx := context.Background()
+	d := Docker().Dry()
+	_, err := d.Images().List(ctx)
+	assert.Equal(t, "dry run: docker images --no-trunc --format={{json .}}", err.Error())
+
+	_, err = d.Images().All().Reference("my-image:*").Label("project", "test").Dangling(false).List(ctx)
+	assert.Equal(t, "dry run: docker images -a --no-trunc --filter reference=my-image:* --filter label=project=test "+
+		"--filter dangling=false --format={{json .}}", err.Error())
+}
+
+func TestDockerRef_ImageHistory(t *testing.T) {
+	_, err := Docker().Dry().ImageHistory(context.Background(), "my-image:latest")
+	assert.Equal(t, "dry run: docker history --no-trunc --human=false --format={{json .}} my-image:latest", err.Error())
+}
+
+func TestDecodeHistory(t *testing.T) {
+	out := `{"Comment":"","CreatedAt":"2024-03-01T12:00:00Z","CreatedBy":"COPY ./my-app /app # buildkit","ID":"sha256:abc","Size":"12345678"}
+{"Comment":"debuerreotype","CreatedAt":"2024-02-01T08:00:00-05:00","CreatedBy":"/bin/sh -c #(nop) ADD file:123 in / ","ID":"<missing>","Size":"0"}`
+	layers, err := decodeHistory(out)
+	assert.NoError(t, err)
+	assert.Len(t, layers, 2)
+	assert.Equal(t, "COPY ./my-app /app # buildkit", layers[0].CreatedBy)
+	assert.Equal(t, int64(12345678), layers[0].Size)
+	assert.Equal(t, 2024, layers[0].CreatedAt.Year())
+	assert.Equal(t, "<missing>", layers[1].ID)
+	assert.Equal(t, int64(0), layers[1].Size)
+
+	_, err = decodeHistory(`{"ID":"sha256:abc","Size":"12MB"}`)
+	assert.Error(t, err)
+}
+
+func TestDockerImageSize_Compare(t *testing.T) {
+	d := Docker().Dry()
+	assert.NoError(t, d.CompareImageSize("my-image:2", "my-image:1").compare(1100, 1000))
+	assert.NoError(t, d.CompareImageSize("my-image:2", "my-image:1").MaxGrowth(100).compare(1100, 1000))
+	assert.Error(t, d.CompareImageSize("my-image:2", "my-image:1").MaxGrowth(99).compare(1100, 1000))
+	assert.NoError(t, d.CompareImageSize("my-image:2", "my-image:1").MaxGrowthPercent(10).compare(1100, 1000))
+	assert.Error(t, d.CompareImageSize("my-image:2", "my-image:1").MaxGrowthPercent(5).compare(1100, 1000))
+	assert.NoError(t, d.CompareImageSize("my-image:2", "my-image:1").MaxGrowth(0).compare(900, 1000))
+
+	var buf bytes.Buffer
+	assert.NoError(t, d.CompareImageSize("my-image:2", "my-image:1").Output(&buf).compare(1500, 1000))
+	assert.Equal(t, "Image 'my-image:2' is 1.5kB, which is +500B (+50.0%) compared to 'my-image:1'\n", buf.String())
+	assert.Panics(t, func() { d.CompareImageSize("my-image:2", "my-image:1").Output(nil) })
+}
+
+func TestDockerImageSize_Run_NoPrevious(t *testing.T) {
+	d, calls := fakeDocker(t, `
+case "$1 $2" in
+"image inspect") echo '[{"Id":"sha256:abc","Size":1500}]' ;;
+esac`)
+	var buf bytes.Buffer
+	assert.NoError(t, d.CompareImageSize("my-image:2", "my-image:1").Output(&buf).Run(context.Background()))
+	assert.Equal(t, "Image 'my-image:2' is 1.5kB, and there is no previous image 'my-image:1' to compare to\n", buf.String())
+	assert.Equal(t, []string{"image inspect my-image:2", "image ls -q my-image:1"}, calls())
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "999B", formatBytes(999))
+	assert.Equal(t, "1.5kB", formatBytes(1500))
+	assert.Equal(t, "431MB", formatBytes(431_000_000))
+	assert.Equal(t, "-2GB", formatSignedBytes(-2_000_000_000))
+}
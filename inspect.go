@@ -0,0 +1,169 @@
+package modmake_docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ContainerInfo is the result of inspecting a container with [DockerRef.InspectContainer].
+type ContainerInfo struct {
+	ID              string          `json:"Id"`
+	Name            string          `json:"Name"`
+	Created         time.Time       `json:"Created"`
+	Path            string          `json:"Path"`
+	Args            []string        `json:"Args"`
+	Image           string          `json:"Image"`
+	RestartCount    int             `json:"RestartCount"`
+	Platform        string          `json:"Platform"`
+	State           ContainerState  `json:"State"`
+	Config          ContainerConfig `json:"Config"`
+	NetworkSettings NetworkSettings `json:"NetworkSettings"`
+	Mounts          []MountPoint    `json:"Mounts"`
+}
+
+// ContainerState describes the running state of a container.
+type ContainerState struct {
+	Status     string           `json:"Status"`
+	Running    bool             `json:"Running"`
+	Paused     bool             `json:"Paused"`
+	Restarting bool             `json:"Restarting"`
+	OOMKilled  bool             `json:"OOMKilled"`
+	Dead       bool             `json:"Dead"`
+	Pid        int              `json:"Pid"`
+	ExitCode   int              `json:"ExitCode"`
+	Error      string           `json:"Error"`
+	StartedAt  time.Time        `json:"StartedAt"`
+	FinishedAt time.Time        `json:"FinishedAt"`
+	Health     *ContainerHealth `json:"Health"`
+}
+
+// ContainerHealth describes the result of a container's health check.
+// This is only present if the container has a health check.
+type ContainerHealth struct {
+	Status        string        `json:"Status"`
+	FailingStreak int           `json:"FailingStreak"`
+	Log           []HealthProbe `json:"Log"`
+}
+
+// HealthProbe is the result of a single health check.
+type HealthProbe struct {
+	Start    time.Time `json:"Start"`
+	End      time.Time `json:"End"`
+	ExitCode int       `json:"ExitCode"`
+	Output   string    `json:"Output"`
+}
+
+// ContainerConfig is the configuration of a container, or the default configuration for containers run from an image.
+type ContainerConfig struct {
+	Hostname     string              `json:"Hostname"`
+	User         string              `json:"User"`
+	Env          []string            `json:"Env"`
+	Cmd          []string            `json:"Cmd"`
+	Entrypoint   []string            `json:"Entrypoint"`
+	Image        string              `json:"Image"`
+	WorkingDir   string              `json:"WorkingDir"`
+	Labels       map[string]string   `json:"Labels"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+	StopSignal   string              `json:"StopSignal"`
+}
+
+// NetworkSettings describes a container's published ports and network connections.
+type NetworkSettings struct {
+	// Ports maps a container port like "80/tcp" to its host bindings.
+	Ports map[string][]PortBinding `json:"Ports"`
+	// Networks maps a network name to the container's connection details.
+	Networks map[string]*NetworkEndpoint `json:"Networks"`
+}
+
+// PortBinding is a host address and port that a container port is published to.
+type PortBinding struct {
+	HostIP   string `json:"HostIp"`
+	HostPort string `json:"HostPort"`
+}
+
+// NetworkEndpoint describes a container's connection to a network.
+type NetworkEndpoint struct {
+	NetworkID   string   `json:"NetworkID"`
+	EndpointID  string   `json:"EndpointID"`
+	Gateway     string   `json:"Gateway"`
+	IPAddress   string   `json:"IPAddress"`
+	IPPrefixLen int      `json:"IPPrefixLen"`
+	MacAddress  string   `json:"MacAddress"`
+	Aliases     []string `json:"Aliases"`
+}
+
+// MountPoint describes a bind mount, volume, or tmpfs mounted in a container.
+type MountPoint struct {
+	Type        string `json:"Type"`
+	Name        string `json:"Name"`
+	Source      string `json:"Source"`
+	Destination string `json:"Destination"`
+	Driver      string `json:"Driver"`
+	Mode        string `json:"Mode"`
+	RW          bool   `json:"RW"`
+	Propagation string `json:"Propagation"`
+}
+
+// ImageInfo is the result of inspecting an image with [DockerRef.InspectImage].
+type ImageInfo struct {
+	ID           string          `json:"Id"`
+	RepoTags     []string        `json:"RepoTags"`
+	RepoDigests  []string        `json:"RepoDigests"`
+	Parent       string          `json:"Parent"`
+	Comment      string          `json:"Comment"`
+	Created      time.Time       `json:"Created"`
+	Author       string          `json:"Author"`
+	Architecture string          `json:"Architecture"`
+	Os           string          `json:"Os"`
+	Variant      string          `json:"Variant"`
+	Size         int64           `json:"Size"`
+	Config       ContainerConfig `json:"Config"`
+	RootFS       ImageRootFS     `json:"RootFS"`
+}
+
+// ImageRootFS lists the layers that make up an image.
+type ImageRootFS struct {
+	Type   string   `json:"Type"`
+	Layers []string `json:"Layers"`
+}
+
+// InspectContainer returns details about a container, referenced by name or ID.
+func (d *DockerRef) InspectContainer(ctx context.Context, container string) (*ContainerInfo, error) {
+	anyBlankPanic(strmap{"container": &container})
+	out, err := d.output(ctx, "container", "inspect", container)
+	if err != nil {
+		return nil, err
+	}
+	info, err := decodeInspect[ContainerInfo](out)
+	if err != nil {
+		return nil, err
+	}
+	// The engine reports names with a leading slash.
+	info.Name = strings.TrimPrefix(info.Name, "/")
+	return info, nil
+}
+
+// InspectImage returns details about an image, referenced by name, tag, digest, or ID.
+func (d *DockerRef) InspectImage(ctx context.Context, image string) (*ImageInfo, error) {
+	anyBlankPanic(strmap{"image": &image})
+	out, err := d.output(ctx, "image", "inspect", image)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInspect[ImageInfo](out)
+}
+
+// decodeInspect decodes the output of "docker inspect", which is a JSON array, for a single object.
+func decodeInspect[T any](out string) (*T, error) {
+	var results []*T
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		return nil, fmt.Errorf("failed to decode inspect output: %w", err)
+	}
+	if len(results) != 1 {
+		return nil, fmt.Errorf("expected 1 inspect result, but got %d", len(results))
+	}
+	return results[0], nil
+}
@@ -0,0 +1,93 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const containerInspectJSON = `[{
+	"Id": "f2b1c4e9d7a3",
+	"Created": "2024-03-01T12:00:00.123456789Z",
+	"Path": "docker-entrypoint.sh",
+	"Args": ["postgres"],
+	"State": {
+		"Status": "running",
+		"Running": true,
+		"Pid": 1234,
+		"ExitCode": 0,
+		"StartedAt": "2024-03-01T12:00:01Z",
+		"FinishedAt": "0001-01-01T00:00:00Z",
+		"Health": {
+			"Status": "healthy",
+			"FailingStreak": 0,
+			"Log": [{"Start": "2024-03-01T12:00:05Z", "End": "2024-03-01T12:00:06Z", "ExitCode": 0, "Output": "ok"}]
+		}
+	},
+	"Image": "sha256:abc123",
+	"Name": "/some-container",
+	"Config": {
+		"Hostname": "f2b1c4e9d7a3",
+		"Env": ["POSTGRES_PASSWORD=secret"],
+		"Cmd": ["postgres"],
+		"Image": "postgres:16",
+		"Labels": {"com.example.build": "123"},
+		"ExposedPorts": {"5432/tcp": {}}
+	},
+	"NetworkSettings": {
+		"Ports": {"5432/tcp": [{"HostIp": "0.0.0.0", "HostPort": "49153"}]},
+		"Networks": {"somenet": {"NetworkID": "n1", "IPAddress": "172.18.0.2", "IPPrefixLen": 16, "Aliases": ["db"]}}
+	},
+	"Mounts": [{"Type": "volume", "Name": "pgdata", "Source": "/var/lib/docker/volumes/pgdata/_data", "Destination": "/var/lib/postgresql/data", "Driver": "local", "RW": true}]
+}]`
+
+const imageInspectJSON = `[{
+	"Id": "sha256:abc123",
+	"RepoTags": ["postgres:16"],
+	"RepoDigests": ["postgres@sha256:def456"],
+	"Created": "2024-02-20T08:30:00Z",
+	"Architecture": "amd64",
+	"Os": "linux",
+	"Size": 431000000,
+	"Config": {"Entrypoint": ["docker-entrypoint.sh"], "Cmd": ["postgres"], "StopSignal": "SIGINT"},
+	"RootFS": {"Type": "layers", "Layers": ["sha256:l1", "sha256:l2"]}
+}]`
+
+func TestDecodeInspect_Container(t *testing.T) {
+	info, err := decodeInspect[ContainerInfo](containerInspectJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, "f2b1c4e9d7a3", info.ID)
+	assert.Equal(t, "running", info.State.Status)
+	assert.True(t, info.State.Running)
+	assert.True(t, info.State.FinishedAt.IsZero())
+	assert.Equal(t, "healthy", info.State.Health.Status)
+	assert.Len(t, info.State.Health.Log, 1)
+	assert.Equal(t, "123", info.Config.Labels["com.example.build"])
+	assert.Equal(t, "49153", info.NetworkSettings.Ports["5432/tcp"][0].HostPort)
+	assert.Equal(t, []string{"db"}, info.NetworkSettings.Networks["somenet"].Aliases)
+	assert.Equal(t, "pgdata", info.Mounts[0].Name)
+}
+
+func TestDecodeInspect_Image(t *testing.T) {
+	info, err := decodeInspect[ImageInfo](imageInspectJSON)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"postgres:16"}, info.RepoTags)
+	assert.Equal(t, int64(431000000), info.Size)
+	assert.Equal(t, "SIGINT", info.Config.StopSignal)
+	assert.Equal(t, []string{"sha256:l1", "sha256:l2"}, info.RootFS.Layers)
+}
+
+func TestDecodeInspect_Empty(t *testing.T) {
+	_, err := decodeInspect[ContainerInfo](`[]`)
+	assert.Error(t, err)
+}
+
+func TestDockerRef_Inspect(t *testing.T) {
+	ctx := context.Background()
+	d := Docker().Dry()
+	_, err := d.InspectContainer(ctx, "some-container")
+	assert.Equal(t, "dry run: docker container inspect some-container", err.Error())
+	_, err = d.InspectImage(ctx, "some-image:latest")
+	assert.Equal(t, "dry run: docker image inspect some-image:latest", err.Error())
+}
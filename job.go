@@ -0,0 +1,124 @@
+package modmake_docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+var _ error = (*ContainerExitError)(nil)
+
+// ContainerExitError is returned when a container exits with a non-zero exit code.
+type ContainerExitError struct {
+	Container string
+	ExitCode  int
+}
+
+func (e *ContainerExitError) Error() string {
+	return fmt.Sprintf("container '%s' exited with code %d", e.Container, e.ExitCode)
+}
+
+// Wait blocks until the container exits, and returns its exit code.
+func (d *DockerRef) Wait(ctx context.Context, container string) (int, error) {
+	anyBlankPanic(strmap{"container": &container})
+	out, err := d.output(ctx, "wait", container)
+	if err != nil {
+		return 0, err
+	}
+	code, err := strconv.Atoi(out)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse exit code '%s': %w", out, err)
+	}
+	return code, nil
+}
+
+// WaitSuccess creates a Task that waits for the container to exit, returning a [ContainerExitError] if it exits with a non-zero exit code.
+func (d *DockerRef) WaitSuccess(container string) Task {
+	anyBlankPanic(strmap{"container": &container})
+	return func(ctx context.Context) error {
+		code, err := d.Wait(ctx, container)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return &ContainerExitError{Container: container, ExitCode: code}
+		}
+		return nil
+	}
+}
+
+// Job creates a [DockerJob], which runs the container to completion as a batch job.
+func (r *DockerRun) Job() *DockerJob {
+	return &DockerJob{run: r}
+}
+
+// DockerJob starts a container detached, follows its logs until it exits, and returns a [ContainerExitError] if it fails.
+// If the context is cancelled or the timeout elapses, then the container is stopped as described in [DockerRun.CancelGracePeriod].
+type DockerJob struct {
+	run     *DockerRun
+	timeout time.Duration
+}
+
+// Timeout sets the maximum time the job may run before it's stopped.
+func (j *DockerJob) Timeout(timeout time.Duration) *DockerJob {
+	if timeout <= 0 {
+		panicf("invalid job timeout '%s'", timeout)
+		return j
+	}
+	j.timeout = timeout
+	return j
+}
+
+func (j *DockerJob) Task() Task {
+	return j.Run
+}
+
+func (j *DockerJob) Run(ctx context.Context) (err error) {
+	if j.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.timeout)
+		defer cancel()
+	}
+	// Docker would remove the container as soon as it exits, before the exit code can be read.
+	run := *j.run
+	run.removeAfterExit = false
+	c, err := run.Start(ctx)
+	if c == nil {
+		return err
+	}
+	if j.run.removeAfterExit {
+		defer func() {
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), defaultCleanupTimeout)
+			defer cancel()
+			if _, rmErr := j.run.d.output(cleanupCtx, "rm", "-f", c.ID()); rmErr != nil && !isNoSuchContainer(rmErr) {
+				err = errors.Join(err, fmt.Errorf("failed to remove job container '%s': %w", c, rmErr))
+			}
+		}()
+	}
+	if err != nil {
+		return err
+	}
+	logsErr := c.Logs().Follow().Run(ctx)
+	if ctx.Err() != nil {
+		return errors.Join(ctx.Err(), run.cleanupCancelled(c.ID()))
+	}
+	code, err := c.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return &ContainerExitError{Container: c.String(), ExitCode: code}
+	}
+	return logsErr
+}
+
+// isNoSuchContainer reports whether an error from a Docker command indicates that the container doesn't exist.
+// This is expected when removing a container that Docker already removed.
+func isNoSuchContainer(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "no such container")
+}
@@ -0,0 +1,40 @@
+package modmake_docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRef_Wait(t *testing.T) {
+	d := Docker().Dry()
+	_, err := d.Wait(context.Background(), "some-container")
+	assert.EqualError(t, err, "dry run: docker wait some-container")
+	isDryRunResult(t, d.WaitSuccess("some-container"), "docker wait some-container")
+}
+
+func TestDockerJob(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Run("migrations:latest").Name("migrate").RemoveAfterExit().Job().Timeout(time.Minute).Task(),
+		"docker run --name=migrate -d migrations:latest")
+	assert.Panics(t, func() { d.Run("migrations:latest").Job().Timeout(0) })
+}
+
+func TestContainerExitError(t *testing.T) {
+	var err error = &ContainerExitError{Container: "migrate", ExitCode: 3}
+	assert.EqualError(t, err, "container 'migrate' exited with code 3")
+	var exitErr *ContainerExitError
+	assert.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.ExitCode)
+}
+
+func TestIsNoSuchContainer(t *testing.T) {
+	err := commandErr([]string{"rm"}, errors.New("exit status 1"), "Error response from daemon: No such container: some-container")
+	assert.True(t, isNoSuchContainer(err))
+	assert.False(t, isNoSuchContainer(commandErr([]string{"rm"}, errors.New("exit status 1"), "Error response from daemon: network somenet not found")))
+	assert.False(t, isNoSuchContainer(errors.New("Cannot connect to the Docker daemon")))
+	assert.False(t, isNoSuchContainer(nil))
+}
@@ -0,0 +1,117 @@
+package modmake_docker
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// Provenance labels are set by the Provenance methods, like [DockerRun.Provenance], so containers, networks, and volumes can be traced back to the build that created them.
+const (
+	LabelStep           = "modmake.step"           // LabelStep is the name of the modmake step that created the object.
+	LabelCommit         = "modmake.commit"         // LabelCommit is the git commit checked out when the object was created, if known.
+	LabelBuildTimestamp = "modmake.buildTimestamp" // LabelBuildTimestamp is when the object was configured, in RFC 3339 format.
+	LabelBuildID        = "modmake.buildID"        // LabelBuildID identifies the build process, see [BuildID].
+)
+
+var (
+	buildIDOnce sync.Once
+	buildID     string
+	commitOnce  sync.Once
+	commit      string
+)
+
+// BuildID returns an identifier that's unique to the current build process.
+// Every object labeled with provenance in the same build has the same [LabelBuildID], so they can be found with a label filter.
+//
+//	Docker().Containers().Label(LabelBuildID, BuildID())
+func BuildID() string {
+	buildIDOnce.Do(func() {
+		var buf [8]byte
+		if _, err := rand.Read(buf[:]); err != nil {
+			buildID = fmt.Sprintf("%x", time.Now().UnixNano())
+			return
+		}
+		buildID = hex.EncodeToString(buf[:])
+	})
+	return buildID
+}
+
+// gitCommit returns the commit hash of HEAD in the current directory, or an empty string if it can't be determined.
+func gitCommit() string {
+	commitOnce.Do(func() {
+		out, err := exec.Command("git", "rev-parse", "HEAD").Output()
+		if err != nil {
+			return
+		}
+		commit = strings.TrimSpace(string(out))
+	})
+	return commit
+}
+
+// provenanceLabels returns the provenance labels for an object created by the given step.
+// The step label is omitted if step is empty, and the commit label is omitted if the commit isn't known.
+func provenanceLabels(step string) []string {
+	var labels []string
+	if step = strings.TrimSpace(step); len(step) > 0 {
+		labels = append(labels, LabelStep+"="+step)
+	}
+	if hash := gitCommit(); len(hash) > 0 {
+		labels = append(labels, LabelCommit+"="+hash)
+	}
+	return append(labels,
+		LabelBuildTimestamp+"="+time.Now().Format(time.RFC3339),
+		LabelBuildID+"="+BuildID(),
+	)
+}
+
+// Label will set a metadata label on the container.
+func (r *DockerRun) Label(key, val string) *DockerRun {
+	anyBlankPanic(strmap{"key": &key, "val": &val})
+	r.labels = append(r.labels, fmt.Sprintf("%s=%s", key, val))
+	return r
+}
+
+// LabelFile will set metadata labels on the container from a file on the host, which is resolved to an absolute path.
+// The file has one "key=value" label per line.
+func (r *DockerRun) LabelFile(path PathString) *DockerRun {
+	absPath, err := path.Abs()
+	if err != nil {
+		panicf("failed to get absolute path for label file: %v", err)
+		return r
+	}
+	r.labelFiles = append(r.labelFiles, absPath.String())
+	return r
+}
+
+// Provenance will set labels on the container identifying the step, git commit, build time, and [BuildID] that created it.
+// The step may be empty if it's not known.
+func (r *DockerRun) Provenance(step string) *DockerRun {
+	r.labels = append(r.labels, provenanceLabels(step)...)
+	return r
+}
+
+// Provenance will set labels on the created network identifying the step, git commit, build time, and [BuildID] that created it.
+// The step may be empty if it's not known.
+func (n *DockerNetwork) Provenance(step string) *DockerNetwork {
+	n.labels = append(n.labels, provenanceLabels(step)...)
+	return n
+}
+
+// Provenance will set labels on the created volume identifying the step, git commit, build time, and [BuildID] that created it.
+// The step may be empty if it's not known.
+func (v *DockerVolume) Provenance(step string) *DockerVolume {
+	v.labels = append(v.labels, provenanceLabels(step)...)
+	return v
+}
+
+// FromThisBuild matches containers labeled by [DockerRun.Provenance] in the current build process.
+func (c *DockerContainers) FromThisBuild() *DockerContainers {
+	return c.Label(LabelBuildID, BuildID())
+}
@@ -0,0 +1,64 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildID(t *testing.T) {
+	assert.Len(t, BuildID(), 16)
+	assert.Equal(t, BuildID(), BuildID())
+}
+
+func TestProvenanceLabels(t *testing.T) {
+	labels := provenanceLabels(" integration ")
+	assert.Equal(t, LabelStep+"=integration", labels[0])
+	assert.Equal(t, LabelBuildID+"="+BuildID(), labels[len(labels)-1])
+	timestamp := strings.TrimPrefix(labels[len(labels)-2], LabelBuildTimestamp+"=")
+	_, err := time.Parse(time.RFC3339, timestamp)
+	assert.NoError(t, err)
+	if hash := gitCommit(); len(hash) > 0 {
+		assert.Contains(t, labels, LabelCommit+"="+hash)
+	}
+
+	labels = provenanceLabels("")
+	for _, label := range labels {
+		assert.False(t, strings.HasPrefix(label, LabelStep+"="), "Step label should be omitted")
+	}
+}
+
+func TestDockerRun_Run_Labels(t *testing.T) {
+	labelFile, _ := Path("./labels").Abs()
+	err := Docker().Dry().Run("some-image:latest").
+		LabelFile(labelFile).
+		Label("project", "test").
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("dry run: docker run --label-file=%s --label project=test some-image:latest", labelFile.String()), err.Error())
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").Label("project", "") })
+}
+
+func TestProvenance(t *testing.T) {
+	d := Docker().Dry()
+	buildLabel := "--label " + LabelBuildID + "=" + BuildID()
+	stepLabel := "--label " + LabelStep + "=integration"
+	for _, task := range []Task{
+		d.Run("some-image:latest").Provenance("integration").Task(),
+		d.Network("test-net").Provenance("integration").Create(),
+		d.Volume("gomod").Provenance("integration").Create(),
+	} {
+		err := task(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), stepLabel)
+		assert.Contains(t, err.Error(), buildLabel)
+	}
+	_, err := d.Containers().FromThisBuild().List(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "--filter label="+LabelBuildID+"="+BuildID())
+}
@@ -0,0 +1,90 @@
+package modmake_docker
+
+import (
+	"strconv"
+	"strings"
+)
+
+// LoggingDriver is a Docker logging driver, used with [DockerRun.LogDriver].
+// Other drivers, including plugins, may be used by name.
+type LoggingDriver string
+
+const (
+	LogDriverJSONFile LoggingDriver = "json-file" // LogDriverJSONFile writes logs to JSON files, which are never rotated by default. This is usually the default.
+	LogDriverLocal    LoggingDriver = "local"     // LogDriverLocal writes logs to compressed files, which are rotated by default.
+	LogDriverNone     LoggingDriver = "none"      // LogDriverNone discards logs, so "docker logs" has nothing to show.
+	LogDriverSyslog   LoggingDriver = "syslog"
+	LogDriverJournald LoggingDriver = "journald"
+)
+
+// LogDriver sets the logging driver of the container, instead of the daemon's default.
+// Logs can only be read with [DockerRef.Logs] for drivers that support it, like [LogDriverJSONFile], [LogDriverLocal], and [LogDriverJournald].
+func (r *DockerRun) LogDriver(driver LoggingDriver) *DockerRun {
+	name := string(driver)
+	anyBlankPanic(strmap{"driver": &name})
+	if driver != r.logDriver {
+		r.logOpts = nil
+	}
+	r.logDriver = driver
+	return r
+}
+
+// LogOpt sets an option of the logging driver, replacing any previous value.
+// A logging driver must be set with [DockerRun.LogDriver] first, since options are specific to the driver.
+func (r *DockerRun) LogOpt(key, val string) *DockerRun {
+	anyBlankPanic(strmap{"key": &key, "val": &val})
+	switch r.logDriver {
+	case "":
+		panic("a logging driver must be set before setting logging options")
+	case LogDriverNone:
+		panicf("logging options are not supported by the '%s' logging driver", r.logDriver)
+	}
+	prefix := key + "="
+	for i, opt := range r.logOpts {
+		if strings.HasPrefix(opt, prefix) {
+			r.logOpts[i] = prefix + val
+			return r
+		}
+	}
+	r.logOpts = append(r.logOpts, prefix+val)
+	return r
+}
+
+// JSONFileLogs uses the [LogDriverJSONFile] logging driver, rotating log files once they reach maxSize, and keeping up to maxFiles of them.
+func (r *DockerRun) JSONFileLogs(maxSize ByteSize, maxFiles int) *DockerRun {
+	return r.LogDriver(LogDriverJSONFile).rotateLogs(maxSize, maxFiles)
+}
+
+// LocalLogs uses the [LogDriverLocal] logging driver, rotating log files once they reach maxSize, and keeping up to maxFiles of them.
+// Docker's defaults for this driver are 20m and 5 files.
+func (r *DockerRun) LocalLogs(maxSize ByteSize, maxFiles int) *DockerRun {
+	return r.LogDriver(LogDriverLocal).rotateLogs(maxSize, maxFiles)
+}
+
+// NoLogs uses the [LogDriverNone] logging driver, discarding the container's logs.
+func (r *DockerRun) NoLogs() *DockerRun {
+	return r.LogDriver(LogDriverNone)
+}
+
+func (r *DockerRun) rotateLogs(maxSize ByteSize, maxFiles int) *DockerRun {
+	if maxSize < 1 {
+		panicf("invalid max log size '%s'", maxSize)
+		return r
+	}
+	if maxFiles < 1 {
+		panicf("invalid max log files '%d'", maxFiles)
+		return r
+	}
+	return r.LogOpt("max-size", maxSize.String()).LogOpt("max-file", strconv.Itoa(maxFiles))
+}
+
+func (r *DockerRun) logArgs() []string {
+	if len(r.logDriver) == 0 {
+		return nil
+	}
+	args := []string{"--log-driver=" + string(r.logDriver)}
+	for _, opt := range r.logOpts {
+		args = append(args, "--log-opt", opt)
+	}
+	return args
+}
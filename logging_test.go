@@ -0,0 +1,35 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRun_Run_Logging(t *testing.T) {
+	err := Docker().Dry().Run("some-image:latest").
+		SetRestartPolicy(RestartAlways).
+		JSONFileLogs(10*MiB, 3).
+		LogOpt("compress", "true").
+		LogOpt("max-file", "5").
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --restart=always --log-driver=json-file --log-opt max-size=10m --log-opt max-file=5 --log-opt compress=true some-image:latest", err.Error())
+
+	run := Docker().Dry().Run("some-image:latest").LocalLogs(20*MiB, 5)
+	assert.Equal(t, []string{"--log-driver=local", "--log-opt", "max-size=20m", "--log-opt", "max-file=5"}, run.logArgs())
+	run.NoLogs()
+	assert.Equal(t, []string{"--log-driver=none"}, run.logArgs())
+	run.LogDriver("fluentd").LogOpt("fluentd-address", "localhost:24224")
+	assert.Equal(t, []string{"--log-driver=fluentd", "--log-opt", "fluentd-address=localhost:24224"}, run.logArgs())
+}
+
+func TestDockerRun_Logging_Invalid(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest")
+	assert.Panics(t, func() { run.LogDriver("") })
+	assert.Panics(t, func() { run.LogOpt("max-size", "10m") })
+	assert.Panics(t, func() { run.JSONFileLogs(0, 3) })
+	assert.Panics(t, func() { run.LocalLogs(MiB, 0) })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").NoLogs().LogOpt("max-size", "10m") })
+}
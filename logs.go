@@ -0,0 +1,241 @@
+package modmake_docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// prefixColors are ANSI color codes cycled through for container prefixes with [DockerLogs.Colored].
+var prefixColors = []int{36, 33, 32, 35, 34, 31}
+
+// Logs creates a [DockerLogs], used to stream the logs of one or more containers.
+// When more than one container is given, each line is prefixed with the name of the container it came from.
+func (d *DockerRef) Logs(containers ...string) *DockerLogs {
+	if len(containers) == 0 {
+		panic("no containers specified")
+	}
+	// Copy the containers, so the caller's slice isn't changed when names are trimmed.
+	containers = append([]string(nil), containers...)
+	for i := range containers {
+		anyBlankPanic(strmap{"container": &containers[i]})
+	}
+	return &DockerLogs{
+		d:          d,
+		containers: containers,
+		tail:       -1,
+		prefix:     len(containers) > 1,
+	}
+}
+
+// DockerLogs encapsulates a "docker logs" command for one or more containers.
+// By default, logs are written to STDOUT and STDERR as Docker reports them.
+type DockerLogs struct {
+	d          *DockerRef
+	containers []string
+	follow     bool
+	timestamps bool
+	since      string
+	until      string
+	tail       int
+	out        io.Writer
+	lineFn     func(container, line string)
+	prefix     bool
+	colored    bool
+}
+
+// Follow will continue streaming new log output until the containers stop, or the context is cancelled.
+func (l *DockerLogs) Follow() *DockerLogs {
+	l.follow = true
+	return l
+}
+
+// Timestamps will include a timestamp with each log line.
+func (l *DockerLogs) Timestamps() *DockerLogs {
+	l.timestamps = true
+	return l
+}
+
+// Since will only show logs written within the given duration before now.
+func (l *DockerLogs) Since(ago time.Duration) *DockerLogs {
+	if ago <= 0 {
+		panicf("invalid duration '%s'", ago)
+		return l
+	}
+	l.since = ago.String()
+	return l
+}
+
+// Until will only show logs written before the given duration before now.
+func (l *DockerLogs) Until(ago time.Duration) *DockerLogs {
+	if ago <= 0 {
+		panicf("invalid duration '%s'", ago)
+		return l
+	}
+	l.until = ago.String()
+	return l
+}
+
+// Tail will only show the given number of lines from the end of the logs.
+func (l *DockerLogs) Tail(lines int) *DockerLogs {
+	if lines < 0 {
+		panicf("invalid number of lines '%d'", lines)
+		return l
+	}
+	l.tail = lines
+	return l
+}
+
+// Output will write logs from both STDOUT and STDERR of the containers to w.
+func (l *DockerLogs) Output(w io.Writer) *DockerLogs {
+	if w == nil {
+		panic("nil writer")
+	}
+	l.out = w
+	return l
+}
+
+// Lines will call fn with each line of log output, instead of writing it.
+// Calls are never made concurrently, even with multiple containers.
+func (l *DockerLogs) Lines(fn func(container, line string)) *DockerLogs {
+	if fn == nil {
+		panic("nil line function")
+	}
+	l.lineFn = fn
+	return l
+}
+
+// Prefix will prefix each line with the name of the container it came from.
+// This is the default when streaming logs from more than one container.
+func (l *DockerLogs) Prefix() *DockerLogs {
+	l.prefix = true
+	return l
+}
+
+// Colored will use a different terminal color for each container's prefix.
+// This implies [DockerLogs.Prefix].
+func (l *DockerLogs) Colored() *DockerLogs {
+	l.prefix = true
+	l.colored = true
+	return l
+}
+
+func (l *DockerLogs) Task() Task {
+	return l.Run
+}
+
+func (l *DockerLogs) Run(ctx context.Context) error {
+	if len(l.containers) == 1 && !l.prefix && l.lineFn == nil {
+		return l.d.commandIO(nil, l.out, l.out, l.args(l.containers[0])...).Run(ctx)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		errs    = make([]error, len(l.containers))
+		prefixW = 0
+	)
+	for _, container := range l.containers {
+		if len(container) > prefixW {
+			prefixW = len(container)
+		}
+	}
+	for i, container := range l.containers {
+		var prefix string
+		if l.prefix {
+			prefix = fmt.Sprintf("%-*s | ", prefixW, container)
+			if l.colored {
+				prefix = fmt.Sprintf("\x1b[%dm%s\x1b[0m", prefixColors[i%len(prefixColors)], prefix)
+			}
+		}
+		stdout := &lineWriter{mu: &mu, container: container, prefix: prefix, out: l.out, lineFn: l.lineFn}
+		stderr := &lineWriter{mu: &mu, container: container, prefix: prefix, out: l.out, lineFn: l.lineFn}
+		if stdout.out == nil {
+			stdout.out = os.Stdout
+			stderr.out = os.Stderr
+		}
+		wg.Add(1)
+		go func(i int, container string) {
+			defer wg.Done()
+			errs[i] = l.d.commandIO(nil, stdout, stderr, l.args(container)...).Run(ctx)
+			stdout.flush()
+			stderr.flush()
+		}(i, container)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+func (l *DockerLogs) args(container string) []string {
+	args := []string{"logs"}
+	if l.follow {
+		args = append(args, "--follow")
+	}
+	if l.timestamps {
+		args = append(args, "--timestamps")
+	}
+	if len(l.since) > 0 {
+		args = append(args, "--since="+l.since)
+	}
+	if len(l.until) > 0 {
+		args = append(args, "--until="+l.until)
+	}
+	if l.tail >= 0 {
+		args = append(args, "--tail="+strconv.Itoa(l.tail))
+	}
+	return append(args, container)
+}
+
+// lineWriter splits written data into lines, and either prefixes and writes them to out, or passes them to lineFn.
+// The mutex is shared between all lineWriters for a [DockerLogs], so lines are never interleaved.
+type lineWriter struct {
+	mu        *sync.Mutex
+	container string
+	prefix    string
+	out       io.Writer
+	lineFn    func(container, line string)
+	buf       bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			return len(p), nil
+		}
+		line := string(w.buf.Next(idx + 1))
+		if err := w.writeLine(strings.TrimSuffix(line, "\n")); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// flush writes any remaining partial line.
+func (w *lineWriter) flush() {
+	if w.buf.Len() > 0 {
+		_ = w.writeLine(w.buf.String())
+		w.buf.Reset()
+	}
+}
+
+func (w *lineWriter) writeLine(line string) error {
+	line = strings.TrimSuffix(line, "\r")
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.lineFn != nil {
+		w.lineFn(w.container, line)
+		return nil
+	}
+	_, err := io.WriteString(w.out, w.prefix+line+"\n")
+	return err
+}
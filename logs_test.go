@@ -0,0 +1,60 @@
+package modmake_docker
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerLogs(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Logs("some-container").Task(), "docker logs some-container")
+	isDryRunResult(t, d.Logs("some-container").Follow().Timestamps().Since(10*time.Minute).Until(time.Minute).Tail(100).Task(),
+		"docker logs --follow --timestamps --since=10m0s --until=1m0s --tail=100 some-container")
+	isDryRunResult(t, d.Logs("some-container").Prefix().Task(), "docker logs some-container")
+}
+
+func TestDockerLogs_Multiple(t *testing.T) {
+	err := Docker().Dry().Logs("db", "cache").Run(context.Background())
+	assert.EqualError(t, err, "dry run: docker logs db\ndry run: docker logs cache")
+
+	containers := []string{" db ", "cache"}
+	err = Docker().Dry().Logs(containers...).Run(context.Background())
+	assert.EqualError(t, err, "dry run: docker logs db\ndry run: docker logs cache")
+	assert.Equal(t, []string{" db ", "cache"}, containers, "Should not have changed the caller's slice")
+}
+
+func TestDockerLogs_Invalid(t *testing.T) {
+	d := Docker().Dry()
+	assert.Panics(t, func() { d.Logs() })
+	assert.Panics(t, func() { d.Logs("db", " ") })
+	assert.Panics(t, func() { d.Logs("db").Tail(-1) })
+	assert.Panics(t, func() { d.Logs("db").Since(0) })
+}
+
+func TestLineWriter(t *testing.T) {
+	var (
+		mu  sync.Mutex
+		buf bytes.Buffer
+	)
+	w := &lineWriter{mu: &mu, container: "db", prefix: "db | ", out: &buf}
+	_, err := w.Write([]byte("first line\nsecond "))
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("line\r\npartial"))
+	assert.NoError(t, err)
+	assert.Equal(t, "db | first line\ndb | second line\n", buf.String())
+	w.flush()
+	assert.Equal(t, "db | first line\ndb | second line\ndb | partial\n", buf.String())
+
+	var lines []string
+	w = &lineWriter{mu: &mu, container: "cache", lineFn: func(container, line string) {
+		lines = append(lines, container+": "+line)
+	}}
+	_, err = w.Write([]byte("ready\nlistening\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cache: ready", "cache: listening"}, lines)
+}
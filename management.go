@@ -3,7 +3,11 @@ package modmake_docker
 import (
 	"context"
 	"errors"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	. "github.com/saylorsolutions/modmake"
 )
@@ -13,15 +17,15 @@ import (
 func (d *DockerRef) RemoveImage(image string) *DockerRemoveImage {
 	anyBlankPanic(strmap{"image": &image})
 	return &DockerRemoveImage{
-		d:     d,
-		image: image,
+		d:      d,
+		images: []string{image},
 	}
 }
 
 type DockerRemoveImage struct {
-	d     *DockerRef
-	image string
-	force bool
+	d      *DockerRef
+	images []string
+	force  bool
 }
 
 // Force will force removal of the referenced Docker image.
@@ -39,7 +43,7 @@ func (r *DockerRemoveImage) Run(ctx context.Context) error {
 	if r.force {
 		args = append(args, "-f")
 	}
-	return r.d.Command(append(args, r.image)...).Run(ctx)
+	return r.d.Command(append(args, r.images...)...).Run(ctx)
 }
 
 // RemoveContainer will attempt to remove a container.
@@ -47,14 +51,14 @@ func (r *DockerRemoveImage) Run(ctx context.Context) error {
 func (d *DockerRef) RemoveContainer(name string) *DockerRemoveContainer {
 	anyBlankPanic(strmap{"name": &name})
 	return &DockerRemoveContainer{
-		d:    d,
-		name: name,
+		d:     d,
+		names: []string{name},
 	}
 }
 
 type DockerRemoveContainer struct {
 	d     *DockerRef
-	name  string
+	names []string
 	force bool
 }
 
@@ -73,13 +77,54 @@ func (r *DockerRemoveContainer) Run(ctx context.Context) error {
 	if r.force {
 		args = append(args, "-f")
 	}
-	return r.d.Command(append(args, r.name)...).Run(ctx)
+	return r.d.Command(append(args, r.names...)...).Run(ctx)
 }
 
 // Stop will attempt to stop a running container with the given name.
-func (d *DockerRef) Stop(name string) Task {
+// Use [DockerStop.Timeout] to change how long the container has to stop before it's killed.
+func (d *DockerRef) Stop(name string) *DockerStop {
 	anyBlankPanic(strmap{"name": &name})
-	return d.Command("stop", name).Run
+	return &DockerStop{d: d, name: name, timeout: -1}
+}
+
+type DockerStop struct {
+	d       *DockerRef
+	name    string
+	signal  string
+	timeout time.Duration
+}
+
+// Signal sets the signal sent to the container to stop it, instead of the container's configured stop signal.
+func (s *DockerStop) Signal(signal string) *DockerStop {
+	anyBlankPanic(strmap{"signal": &signal})
+	s.signal = signal
+	return s
+}
+
+// Timeout sets how long to wait for the container to stop before killing it.
+// The value is rounded to whole seconds.
+func (s *DockerStop) Timeout(timeout time.Duration) *DockerStop {
+	if timeout < 0 {
+		panicf("invalid stop timeout '%s'", timeout)
+		return s
+	}
+	s.timeout = timeout
+	return s
+}
+
+func (s *DockerStop) Task() Task {
+	return s.Run
+}
+
+func (s *DockerStop) Run(ctx context.Context) error {
+	args := []string{"stop"}
+	if len(s.signal) > 0 {
+		args = append(args, "--signal="+s.signal)
+	}
+	if s.timeout >= 0 {
+		args = append(args, "-t", durationSeconds(s.timeout))
+	}
+	return s.d.Command(append(args, s.name)...).Run(ctx)
 }
 
 // Start will attempt to start a container with the given name.
@@ -88,6 +133,168 @@ func (d *DockerRef) Start(name string) Task {
 	return d.Command("start", name).Run
 }
 
+// Kill will kill a running container with the given name.
+// By default, the container is sent SIGKILL.
+func (d *DockerRef) Kill(name string) *DockerKill {
+	anyBlankPanic(strmap{"name": &name})
+	return &DockerKill{d: d, name: name}
+}
+
+type DockerKill struct {
+	d      *DockerRef
+	name   string
+	signal string
+}
+
+// Signal sets the signal sent to the container, like "SIGHUP" or "HUP".
+func (k *DockerKill) Signal(signal string) *DockerKill {
+	anyBlankPanic(strmap{"signal": &signal})
+	k.signal = signal
+	return k
+}
+
+func (k *DockerKill) Task() Task {
+	return k.Run
+}
+
+func (k *DockerKill) Run(ctx context.Context) error {
+	args := []string{"kill"}
+	if len(k.signal) > 0 {
+		args = append(args, "--signal="+k.signal)
+	}
+	return k.d.Command(append(args, k.name)...).Run(ctx)
+}
+
+// Restart will restart a container with the given name.
+func (d *DockerRef) Restart(name string) *DockerRestart {
+	anyBlankPanic(strmap{"name": &name})
+	return &DockerRestart{d: d, name: name, timeout: -1}
+}
+
+type DockerRestart struct {
+	d       *DockerRef
+	name    string
+	timeout time.Duration
+}
+
+// Timeout sets how long to wait for the container to stop before killing it.
+// The value is rounded to whole seconds.
+func (r *DockerRestart) Timeout(timeout time.Duration) *DockerRestart {
+	if timeout < 0 {
+		panicf("invalid restart timeout '%s'", timeout)
+		return r
+	}
+	r.timeout = timeout
+	return r
+}
+
+func (r *DockerRestart) Task() Task {
+	return r.Run
+}
+
+func (r *DockerRestart) Run(ctx context.Context) error {
+	args := []string{"restart"}
+	if r.timeout >= 0 {
+		args = append(args, "-t", durationSeconds(r.timeout))
+	}
+	return r.d.Command(append(args, r.name)...).Run(ctx)
+}
+
+// Pause will suspend all processes in a container with the given name.
+func (d *DockerRef) Pause(name string) Task {
+	anyBlankPanic(strmap{"name": &name})
+	return d.Command("pause", name).Run
+}
+
+// Unpause will resume all processes in a paused container with the given name.
+func (d *DockerRef) Unpause(name string) Task {
+	anyBlankPanic(strmap{"name": &name})
+	return d.Command("unpause", name).Run
+}
+
+// Rename will change the name of a container.
+func (d *DockerRef) Rename(name, newName string) Task {
+	anyBlankPanic(strmap{"name": &name, "newName": &newName})
+	return d.Command("rename", name, newName).Run
+}
+
+// Update will change the resource limits or restart policy of an existing container with the given name.
+func (d *DockerRef) Update(name string) *DockerUpdate {
+	anyBlankPanic(strmap{"name": &name})
+	return &DockerUpdate{d: d, name: name}
+}
+
+type DockerUpdate struct {
+	d    *DockerRef
+	name string
+	args []string
+}
+
+// CPUs limits the number of CPUs the container may use, like 1.5.
+func (u *DockerUpdate) CPUs(cpus float64) *DockerUpdate {
+	u.args = append(u.args, cpusArg(cpus))
+	return u
+}
+
+// CPUShares sets the relative CPU weight of the container.
+func (u *DockerUpdate) CPUShares(shares int) *DockerUpdate {
+	u.args = append(u.args, cpuSharesArg(shares))
+	return u
+}
+
+// CPUSetCPUs restricts the CPUs the container may run on, like "0-3" or "0,1".
+func (u *DockerUpdate) CPUSetCPUs(cpus string) *DockerUpdate {
+	u.args = append(u.args, cpuSetArg(cpus))
+	return u
+}
+
+// Memory limits the memory the container may use.
+func (u *DockerUpdate) Memory(size ByteSize) *DockerUpdate {
+	u.args = append(u.args, memoryArg(size))
+	return u
+}
+
+// MemorySwap limits the total of memory and swap the container may use.
+// Use [Unlimited] to allow unlimited swap.
+func (u *DockerUpdate) MemorySwap(size ByteSize) *DockerUpdate {
+	u.args = append(u.args, memorySwapArg(size))
+	return u
+}
+
+// PidsLimit limits the number of processes in the container.
+// A value of -1 allows unlimited processes.
+func (u *DockerUpdate) PidsLimit(limit int) *DockerUpdate {
+	u.args = append(u.args, pidsLimitArg(limit))
+	return u
+}
+
+// SetRestartPolicy changes the [RestartPolicy] of the container.
+func (u *DockerUpdate) SetRestartPolicy(policy RestartPolicy) *DockerUpdate {
+	if _, ok := knownRestartPolicies[policy]; !ok {
+		panicf("unknown restart policy '%s'", policy)
+		return u
+	}
+	u.args = append(u.args, "--restart="+string(policy))
+	return u
+}
+
+func (u *DockerUpdate) Task() Task {
+	return u.Run
+}
+
+func (u *DockerUpdate) Run(ctx context.Context) error {
+	if len(u.args) == 0 {
+		return errors.New("no updates specified")
+	}
+	args := append([]string{"update"}, u.args...)
+	return u.d.Command(append(args, u.name)...).Run(ctx)
+}
+
+// durationSeconds formats a duration as a whole number of seconds, as expected by timeout flags.
+func durationSeconds(d time.Duration) string {
+	return strconv.Itoa(int(d.Round(time.Second) / time.Second))
+}
+
 type DockerExec struct {
 	ref                                    *DockerRef
 	containerName                          string
@@ -95,6 +302,8 @@ type DockerExec struct {
 	detached, interactive, tty, privileged bool
 	userGroup                              string
 	workingDir                             PathString
+	stdin                                  io.Reader
+	stdout, stderr                         io.Writer
 }
 
 func (d *DockerRef) Exec(containerName string, cmd string, args ...string) *DockerExec {
@@ -103,6 +312,12 @@ func (d *DockerRef) Exec(containerName string, cmd string, args ...string) *Dock
 }
 
 func (e *DockerExec) Detached() *DockerExec {
+	if e.stdin != nil {
+		panic("a detached command cannot be used with supplied stdin")
+	}
+	if e.stdout != nil || e.stderr != nil {
+		panic("a detached command cannot be used with supplied stdout or stderr")
+	}
 	e.detached = true
 	e.interactive = false
 	e.tty = false
@@ -110,6 +325,9 @@ func (e *DockerExec) Detached() *DockerExec {
 }
 
 func (e *DockerExec) InteractiveTerm() *DockerExec {
+	if e.stdin != nil {
+		panic("an interactive terminal cannot be used with supplied stdin")
+	}
 	e.interactive = true
 	e.tty = true
 	e.detached = false
@@ -162,7 +380,14 @@ func (e *DockerExec) Run(ctx context.Context) error {
 		args = append(args, "-w", wd)
 	}
 	args = append(append(args, e.containerName), e.cmd...)
-	return e.ref.Command(args...).Run(ctx)
+	if e.stdin == nil && e.stdout == nil && e.stderr == nil {
+		return e.ref.Command(args...).Run(ctx)
+	}
+	stdin := e.stdin
+	if stdin == nil && e.interactive {
+		stdin = os.Stdin
+	}
+	return e.ref.commandIO(stdin, e.stdout, e.stderr, args...).Run(ctx)
 }
 
 type DockerLogin struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	. "github.com/saylorsolutions/modmake"
 	"github.com/stretchr/testify/assert"
@@ -92,3 +93,39 @@ func isDryRunResult(t *testing.T, task Task, command string) {
 	assert.True(t, ok, "Should have been a dry run error")
 	assert.Equal(t, "dry run: "+command, err.Error())
 }
+
+func TestDocker_Stop_Options(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Stop("some-container").Timeout(30*time.Second).Task(),
+		"docker stop -t 30 some-container")
+	isDryRunResult(t, d.Stop("some-container").Signal("SIGINT").Timeout(0).Task(),
+		"docker stop --signal=SIGINT -t 0 some-container")
+}
+
+func TestDocker_Lifecycle(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Kill("some-container").Task(), "docker kill some-container")
+	isDryRunResult(t, d.Kill("some-container").Signal("SIGHUP").Task(), "docker kill --signal=SIGHUP some-container")
+	isDryRunResult(t, d.Restart("some-container").Task(), "docker restart some-container")
+	isDryRunResult(t, d.Restart("some-container").Timeout(1500*time.Millisecond).Task(), "docker restart -t 2 some-container")
+	isDryRunResult(t, d.Pause("some-container"), "docker pause some-container")
+	isDryRunResult(t, d.Unpause("some-container"), "docker unpause some-container")
+	isDryRunResult(t, d.Rename("some-container", "other-container"), "docker rename some-container other-container")
+}
+
+func TestDocker_Update(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Update("some-container").
+		CPUs(1.5).
+		CPUShares(512).
+		CPUSetCPUs("0-3").
+		Memory(512*MiB).
+		MemorySwap(Unlimited).
+		PidsLimit(100).
+		SetRestartPolicy(RestartUnlessStopped).
+		Task(),
+		"docker update --cpus=1.5 --cpu-shares=512 --cpuset-cpus=0-3 --memory=512m --memory-swap=-1 --pids-limit=100 --restart=unless-stopped some-container")
+	err := d.Update("some-container").Run(context.Background())
+	assert.EqualError(t, err, "no updates specified")
+	assert.Panics(t, func() { d.Update("some-container").CPUs(0) })
+}
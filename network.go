@@ -0,0 +1,201 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// Network creates a [DockerNetwork], used to manage the named network.
+func (d *DockerRef) Network(name string) *DockerNetwork {
+	anyBlankPanic(strmap{"name": &name})
+	return &DockerNetwork{d: d, name: name}
+}
+
+// DockerNetwork references a network by name, and provides options for creating it.
+// The creation options have no effect on a network that already exists.
+type DockerNetwork struct {
+	d          *DockerRef
+	name       string
+	driver     string
+	subnet     string
+	gateway    string
+	internal   bool
+	attachable bool
+	labels     []string
+}
+
+// NetworkInfo is the result of inspecting a network with [DockerNetwork.Inspect].
+type NetworkInfo struct {
+	ID         string                       `json:"Id"`
+	Name       string                       `json:"Name"`
+	Created    time.Time                    `json:"Created"`
+	Scope      string                       `json:"Scope"`
+	Driver     string                       `json:"Driver"`
+	EnableIPv6 bool                         `json:"EnableIPv6"`
+	IPAM       NetworkIPAM                  `json:"IPAM"`
+	Internal   bool                         `json:"Internal"`
+	Attachable bool                         `json:"Attachable"`
+	Containers map[string]*NetworkContainer `json:"Containers"`
+	Options    map[string]string            `json:"Options"`
+	Labels     map[string]string            `json:"Labels"`
+}
+
+// NetworkIPAM describes the IP address management configuration of a network.
+type NetworkIPAM struct {
+	Driver string              `json:"Driver"`
+	Config []NetworkIPAMConfig `json:"Config"`
+}
+
+// NetworkIPAMConfig is an address pool used by a network.
+type NetworkIPAMConfig struct {
+	Subnet  string `json:"Subnet"`
+	Gateway string `json:"Gateway"`
+}
+
+// NetworkContainer describes a container's endpoint on a network.
+// [NetworkInfo.Containers] is keyed by container ID.
+type NetworkContainer struct {
+	Name        string `json:"Name"`
+	EndpointID  string `json:"EndpointID"`
+	MacAddress  string `json:"MacAddress"`
+	IPv4Address string `json:"IPv4Address"`
+	IPv6Address string `json:"IPv6Address"`
+}
+
+// Name returns the name of the network.
+func (n *DockerNetwork) Name() string {
+	return n.name
+}
+
+// Driver sets the network driver used when creating the network, like "bridge" or "overlay".
+func (n *DockerNetwork) Driver(driver string) *DockerNetwork {
+	anyBlankPanic(strmap{"driver": &driver})
+	n.driver = driver
+	return n
+}
+
+// Subnet sets the subnet of the network in CIDR format, like "172.28.0.0/16".
+func (n *DockerNetwork) Subnet(cidr string) *DockerNetwork {
+	anyBlankPanic(strmap{"cidr": &cidr})
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		panicf("invalid subnet '%s': %v", cidr, err)
+		return n
+	}
+	n.subnet = cidr
+	return n
+}
+
+// Gateway sets the gateway address of the network's subnet.
+func (n *DockerNetwork) Gateway(ip string) *DockerNetwork {
+	anyBlankPanic(strmap{"ip": &ip})
+	if net.ParseIP(ip) == nil {
+		panicf("invalid gateway address '%s'", ip)
+		return n
+	}
+	n.gateway = ip
+	return n
+}
+
+// Internal will restrict external access to the network, isolating connected containers from the outside world.
+func (n *DockerNetwork) Internal() *DockerNetwork {
+	n.internal = true
+	return n
+}
+
+// Attachable will allow standalone containers to connect to a swarm scoped network.
+func (n *DockerNetwork) Attachable() *DockerNetwork {
+	n.attachable = true
+	return n
+}
+
+// Label will set a metadata label on the created network.
+func (n *DockerNetwork) Label(key, val string) *DockerNetwork {
+	anyBlankPanic(strmap{"key": &key, "val": &val})
+	n.labels = append(n.labels, fmt.Sprintf("%s=%s", key, val))
+	return n
+}
+
+// Create will create the network, which fails if it already exists.
+func (n *DockerNetwork) Create() Task {
+	return func(ctx context.Context) error {
+		return n.d.Command(n.createArgs()...).Run(ctx)
+	}
+}
+
+func (n *DockerNetwork) createArgs() []string {
+	args := []string{"network", "create"}
+	if len(n.driver) > 0 {
+		args = append(args, "--driver="+n.driver)
+	}
+	if len(n.subnet) > 0 {
+		args = append(args, "--subnet="+n.subnet)
+	}
+	if len(n.gateway) > 0 {
+		args = append(args, "--gateway="+n.gateway)
+	}
+	if n.internal {
+		args = append(args, "--internal")
+	}
+	if n.attachable {
+		args = append(args, "--attachable")
+	}
+	for _, label := range n.labels {
+		args = append(args, "--label", label)
+	}
+	return append(args, n.name)
+}
+
+// Ensure will create the network if it doesn't already exist.
+func (n *DockerNetwork) Ensure() Task {
+	return func(ctx context.Context) error {
+		exists, err := n.Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+		return n.Create().Run(ctx)
+	}
+}
+
+// Exists reports whether the network exists.
+func (n *DockerNetwork) Exists(ctx context.Context) (bool, error) {
+	return n.d.objectExists(ctx, "network", n.name)
+}
+
+// Inspect returns details about the network.
+func (n *DockerNetwork) Inspect(ctx context.Context) (*NetworkInfo, error) {
+	out, err := n.d.output(ctx, "network", "inspect", n.name)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInspect[NetworkInfo](out)
+}
+
+// Connect will connect a running container to the network, with optional network aliases.
+func (n *DockerNetwork) Connect(container string, aliases ...string) Task {
+	anyBlankPanic(strmap{"container": &container})
+	args := []string{"network", "connect"}
+	for _, alias := range aliases {
+		anyBlankPanic(strmap{"alias": &alias})
+		args = append(args, "--alias="+alias)
+	}
+	return n.d.Command(append(args, n.name, container)...).Run
+}
+
+// Disconnect will disconnect a container from the network.
+func (n *DockerNetwork) Disconnect(container string) Task {
+	anyBlankPanic(strmap{"container": &container})
+	return n.d.Command("network", "disconnect", n.name, container).Run
+}
+
+// Remove will remove the network.
+// All containers must be disconnected from the network first.
+func (n *DockerNetwork) Remove() Task {
+	return n.d.Command("network", "rm", n.name).Run
+}
@@ -0,0 +1,83 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerNetwork_Create(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Network("somenet").Create(), "docker network create somenet")
+	isDryRunResult(t, d.Network("somenet").
+		Driver("bridge").
+		Subnet("172.28.0.0/16").
+		Gateway("172.28.0.1").
+		Internal().
+		Attachable().
+		Label("project", "test").
+		Create(),
+		"docker network create --driver=bridge --subnet=172.28.0.0/16 --gateway=172.28.0.1 --internal --attachable --label project=test somenet")
+}
+
+func TestDockerNetwork_Invalid(t *testing.T) {
+	d := Docker().Dry()
+	assert.Panics(t, func() { d.Network(" ") })
+	assert.Panics(t, func() { d.Network("somenet").Subnet("172.28.0.0") })
+	assert.Panics(t, func() { d.Network("somenet").Gateway("gateway") })
+}
+
+func TestDockerNetwork_Ensure(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Network("somenet").Ensure(), "docker network ls --filter name=^somenet$ -q")
+	_, err := d.Network("some.net").Exists(context.Background())
+	assert.Equal(t, `dry run: docker network ls --filter name=^some\.net$ -q`, err.Error())
+}
+
+func TestDockerNetwork_Containers(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Network("somenet").Connect("some-container"),
+		"docker network connect somenet some-container")
+	isDryRunResult(t, d.Network("somenet").Connect("some-container", "db", "postgres"),
+		"docker network connect --alias=db --alias=postgres somenet some-container")
+	isDryRunResult(t, d.Network("somenet").Disconnect("some-container"),
+		"docker network disconnect somenet some-container")
+	isDryRunResult(t, d.Network("somenet").Remove(), "docker network rm somenet")
+}
+
+func TestDecodeInspect_Network(t *testing.T) {
+	info, err := decodeInspect[NetworkInfo](`[{
+		"Name": "somenet",
+		"Id": "n1",
+		"Created": "2024-03-01T12:00:00.5Z",
+		"Scope": "local",
+		"Driver": "bridge",
+		"IPAM": {"Driver": "default", "Config": [{"Subnet": "172.28.0.0/16", "Gateway": "172.28.0.1"}]},
+		"Internal": true,
+		"Containers": {"c1": {"Name": "db", "IPv4Address": "172.28.0.2/16"}},
+		"Labels": {"project": "test"}
+	}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, "somenet", info.Name)
+	assert.True(t, info.Internal)
+	assert.Equal(t, "172.28.0.0/16", info.IPAM.Config[0].Subnet)
+	assert.Equal(t, "db", info.Containers["c1"].Name)
+	assert.Equal(t, "test", info.Labels["project"])
+}
+
+func TestDockerNetwork_Ensure_Missing(t *testing.T) {
+	d, calls := fakeDocker(t, `
+case "$1 $2" in
+"network inspect") echo "Error response from daemon: network somenet not found" >&2; exit 1 ;;
+esac`)
+	assert.NoError(t, d.Network("somenet").Ensure().Run(context.Background()))
+	assert.Equal(t, []string{"network ls --filter name=^somenet$ -q", "network create somenet"}, calls())
+
+	d, calls = fakeDocker(t, `echo 0123456789ab`)
+	exists, err := d.Network("somenet").Exists(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, d.Network("somenet").Ensure().Run(context.Background()))
+	assert.Equal(t, []string{"network ls --filter name=^somenet$ -q", "network ls --filter name=^somenet$ -q"}, calls())
+}
@@ -0,0 +1,145 @@
+package modmake_docker
+
+import (
+	"net"
+	"strings"
+)
+
+// HostGateway is used with [DockerRun.AddHost] to resolve a name to the IP address of the container host.
+const HostGateway = "host-gateway"
+
+// networkEndpoint is an additional network connected to a container after it's created.
+type networkEndpoint struct {
+	network string
+	aliases []string
+	ipv4    string
+	ipv6    string
+}
+
+// ConnectNetwork will allow this container to communicate using the named network, and to be reached by the given aliases on it.
+// The network must already exist, see [DockerRef.Network] to create it.
+// The first network is connected when the container is created, and each other network is connected before the container is started.
+func (r *DockerRun) ConnectNetwork(network string, aliases ...string) *DockerRun {
+	anyBlankPanic(strmap{"network": &network})
+	for i := range aliases {
+		anyBlankPanic(strmap{"alias": &aliases[i]})
+	}
+	if len(r.networkConn) == 0 || r.networkConn == network {
+		r.networkConn = network
+		for _, alias := range aliases {
+			r.networkAliases = appendMissing(r.networkAliases, alias)
+		}
+		return r
+	}
+	for i := range r.extraNetworks {
+		if r.extraNetworks[i].network == network {
+			for _, alias := range aliases {
+				r.extraNetworks[i].aliases = appendMissing(r.extraNetworks[i].aliases, alias)
+			}
+			return r
+		}
+	}
+	endpoint := networkEndpoint{network: network}
+	for _, alias := range aliases {
+		endpoint.aliases = appendMissing(endpoint.aliases, alias)
+	}
+	r.extraNetworks = append(r.extraNetworks, endpoint)
+	return r
+}
+
+// NetworkAlias will add aliases that the container can be reached by on the first network passed to [DockerRun.ConnectNetwork].
+func (r *DockerRun) NetworkAlias(aliases ...string) *DockerRun {
+	if len(r.networkConn) == 0 {
+		panic("a network must be connected before adding network aliases")
+	}
+	return r.ConnectNetwork(r.networkConn, aliases...)
+}
+
+// IP sets the IPv4 or IPv6 address of the container on the first network passed to [DockerRun.ConnectNetwork].
+// The network must have been created with a subnet that includes the address.
+func (r *DockerRun) IP(ip string) *DockerRun {
+	anyBlankPanic(strmap{"ip": &ip})
+	if len(r.networkConn) == 0 {
+		panic("a network must be connected before setting an IP address")
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		panicf("invalid IP address '%s'", ip)
+		return r
+	}
+	if parsed.To4() != nil {
+		r.ipv4 = ip
+	} else {
+		r.ipv6 = ip
+	}
+	return r
+}
+
+// MacAddress sets the MAC address of the container, like "02:42:ac:11:00:02".
+func (r *DockerRun) MacAddress(mac string) *DockerRun {
+	anyBlankPanic(strmap{"mac": &mac})
+	if _, err := net.ParseMAC(mac); err != nil {
+		panicf("invalid MAC address '%s'", mac)
+		return r
+	}
+	r.macAddress = mac
+	return r
+}
+
+// AddHost will add an entry to the container's hosts file, so the name resolves to the given IP address.
+// Use [HostGateway] as the IP address to resolve the name to the container host.
+func (r *DockerRun) AddHost(name, ip string) *DockerRun {
+	anyBlankPanic(strmap{"name": &name, "ip": &ip})
+	if strings.ContainsAny(name, ": \t") {
+		panicf("invalid host name '%s'", name)
+		return r
+	}
+	if ip != HostGateway && net.ParseIP(ip) == nil {
+		panicf("invalid IP address '%s'", ip)
+		return r
+	}
+	r.extraHosts = append(r.extraHosts, name+":"+ip)
+	return r
+}
+
+// AddHostDockerInternal will resolve "host.docker.internal" to the container host, as Docker Desktop does by default.
+// This makes it possible to reach services on the host the same way on Linux.
+func (r *DockerRun) AddHostDockerInternal() *DockerRun {
+	return r.AddHost("host.docker.internal", HostGateway)
+}
+
+// DNS sets the DNS servers used by the container.
+func (r *DockerRun) DNS(servers ...string) *DockerRun {
+	for _, server := range servers {
+		if net.ParseIP(server) == nil {
+			panicf("invalid DNS server '%s'", server)
+			return r
+		}
+		r.dns = appendMissing(r.dns, "--dns="+server)
+	}
+	return r
+}
+
+// DNSSearch sets the domains searched by the container when resolving names that aren't fully qualified.
+func (r *DockerRun) DNSSearch(domains ...string) *DockerRun {
+	for i := range domains {
+		anyBlankPanic(strmap{"domain": &domains[i]})
+		r.dns = appendMissing(r.dns, "--dns-search="+domains[i])
+	}
+	return r
+}
+
+// connectArgs returns the arguments to connect a container to the network.
+func (e networkEndpoint) connectArgs(container string) []string {
+	args := []string{"network", "connect"}
+	for _, alias := range e.aliases {
+		args = append(args, "--alias="+alias)
+	}
+	if len(e.ipv4) > 0 {
+		args = append(args, "--ip="+e.ipv4)
+	}
+	if len(e.ipv6) > 0 {
+		args = append(args, "--ip6="+e.ipv6)
+	}
+	return append(args, e.network, container)
+}
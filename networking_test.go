@@ -0,0 +1,62 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRun_Run_Networking(t *testing.T) {
+	err := Docker().Dry().Run("some-image:latest").
+		ConnectNetwork("somenet", "db").
+		NetworkAlias("postgres", "db").
+		IP("172.20.0.5").
+		IP("fd00::5").
+		MacAddress("02:42:ac:11:00:02").
+		AddHost("registry.local", "10.0.0.2").
+		AddHostDockerInternal().
+		DNS("1.1.1.1", "8.8.8.8").
+		DNSSearch("example.com").
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --network=somenet --network-alias=db --network-alias=postgres --ip=172.20.0.5 --ip6=fd00::5 --mac-address=02:42:ac:11:00:02 --add-host=registry.local:10.0.0.2 --add-host=host.docker.internal:host-gateway --dns=1.1.1.1 --dns=8.8.8.8 --dns-search=example.com some-image:latest", err.Error())
+}
+
+func TestDockerRun_Run_MultipleNetworks(t *testing.T) {
+	err := Docker().Dry().Run("some-image:latest").
+		Name("some-container").
+		ConnectNetwork("frontend", "web").
+		ConnectNetwork("backend", "api").
+		ConnectNetwork("backend", "app").
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker create --name=some-container --network=frontend --network-alias=web some-image:latest", err.Error())
+
+	d, calls := fakeDocker(t, `[ "$1" = create ] && echo 0123456789ab; true`)
+	err = d.Run("some-image:latest").
+		Name("some-container").
+		ConnectNetwork("frontend", "web").
+		ConnectNetwork("backend", "api").
+		ConnectNetwork("backend", "app").
+		Detached().
+		Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"create --name=some-container --network=frontend --network-alias=web some-image:latest",
+		"network connect --alias=api --alias=app backend 0123456789ab",
+		"start 0123456789ab",
+	}, calls())
+}
+
+func TestDockerRun_Networking_Invalid(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest")
+	assert.Panics(t, func() { run.NetworkAlias("db") })
+	assert.Panics(t, func() { run.IP("172.20.0.5") })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").ConnectNetwork("somenet").IP("not-an-ip") })
+	assert.Panics(t, func() { run.MacAddress("02:42") })
+	assert.Panics(t, func() { run.AddHost("bad:name", "10.0.0.2") })
+	assert.Panics(t, func() { run.AddHost("registry.local", "localhost") })
+	assert.Panics(t, func() { run.DNS("dns.example.com") })
+	assert.Panics(t, func() { run.DNSSearch("") })
+}
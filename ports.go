@@ -0,0 +1,180 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PortProtocol is the protocol of a published port.
+type PortProtocol string
+
+const (
+	ProtocolTCP  PortProtocol = "tcp" // ProtocolTCP is the default protocol of a published port.
+	ProtocolUDP  PortProtocol = "udp"
+	ProtocolSCTP PortProtocol = "sctp"
+)
+
+var knownProtocols = map[PortProtocol]struct{}{
+	ProtocolTCP:  {},
+	ProtocolUDP:  {},
+	ProtocolSCTP: {},
+}
+
+// DockerPort is a port publishing specification used with [DockerRun.Publish].
+// By default, a random host port is bound on all host interfaces, which can be found with [Container.Port] once the container is started.
+type DockerPort struct {
+	hostIP                       string
+	hostStart, hostEnd           int
+	containerStart, containerEnd int
+	protocol                     PortProtocol
+}
+
+// ContainerPort creates a [DockerPort] to publish a single container port.
+func ContainerPort(port int) *DockerPort {
+	return ContainerPortRange(port, port)
+}
+
+// ContainerPortRange creates a [DockerPort] to publish a range of container ports, inclusive.
+func ContainerPortRange(start, end int) *DockerPort {
+	validatePortRange(start, end)
+	return &DockerPort{containerStart: start, containerEnd: end, protocol: ProtocolTCP}
+}
+
+func validatePortRange(start, end int) {
+	if start < 1 || end > 65535 || start > end {
+		panicf("invalid port range '%d-%d'", start, end)
+	}
+}
+
+// HostPort binds the container port to a specific host port, instead of a random one.
+func (p *DockerPort) HostPort(port int) *DockerPort {
+	return p.HostPortRange(port, port)
+}
+
+// HostPortRange binds the container ports to a range of host ports, inclusive.
+// When publishing a single container port, Docker picks a free host port from the range.
+// Otherwise, the ranges must be the same size.
+func (p *DockerPort) HostPortRange(start, end int) *DockerPort {
+	validatePortRange(start, end)
+	if p.containerStart != p.containerEnd && end-start != p.containerEnd-p.containerStart {
+		panicf("host port range '%d-%d' doesn't match container port range '%d-%d'", start, end, p.containerStart, p.containerEnd)
+		return p
+	}
+	p.hostStart, p.hostEnd = start, end
+	return p
+}
+
+// HostIP binds the port to a single host interface, like "127.0.0.1" to avoid exposing it on the network.
+func (p *DockerPort) HostIP(ip string) *DockerPort {
+	anyBlankPanic(strmap{"ip": &ip})
+	if net.ParseIP(ip) == nil {
+		panicf("invalid host IP '%s'", ip)
+		return p
+	}
+	p.hostIP = ip
+	return p
+}
+
+// Localhost binds the port to the loopback interface only.
+func (p *DockerPort) Localhost() *DockerPort {
+	return p.HostIP("127.0.0.1")
+}
+
+// Protocol sets the [PortProtocol] of the port.
+func (p *DockerPort) Protocol(protocol PortProtocol) *DockerPort {
+	if _, ok := knownProtocols[protocol]; !ok {
+		panicf("unknown port protocol '%s'", protocol)
+		return p
+	}
+	p.protocol = protocol
+	return p
+}
+
+// UDP publishes the port using UDP, instead of TCP.
+func (p *DockerPort) UDP() *DockerPort {
+	return p.Protocol(ProtocolUDP)
+}
+
+// SCTP publishes the port using SCTP, instead of TCP.
+func (p *DockerPort) SCTP() *DockerPort {
+	return p.Protocol(ProtocolSCTP)
+}
+
+// String formats the port as a value for the "-p" flag.
+func (p *DockerPort) String() string {
+	var buf strings.Builder
+	if len(p.hostIP) > 0 {
+		if strings.Contains(p.hostIP, ":") {
+			buf.WriteString("[" + p.hostIP + "]:")
+		} else {
+			buf.WriteString(p.hostIP + ":")
+		}
+	}
+	if p.hostStart > 0 {
+		buf.WriteString(formatPortRange(p.hostStart, p.hostEnd) + ":")
+	} else if len(p.hostIP) > 0 {
+		buf.WriteString(":")
+	}
+	buf.WriteString(formatPortRange(p.containerStart, p.containerEnd))
+	if p.protocol != ProtocolTCP {
+		buf.WriteString("/" + string(p.protocol))
+	}
+	return buf.String()
+}
+
+func formatPortRange(start, end int) string {
+	if start == end {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d-%d", start, end)
+}
+
+// Publish will publish container ports as specified by each [DockerPort].
+func (r *DockerRun) Publish(ports ...*DockerPort) *DockerRun {
+	for _, p := range ports {
+		if p == nil {
+			panic("nil port")
+		}
+		r.portMappings = append(r.portMappings, p.String())
+	}
+	return r
+}
+
+// PublishRandomPort will publish a container port to a random host port on the loopback interface.
+// Use [Container.Port] to find the host port after the container is started.
+func (r *DockerRun) PublishRandomPort(container int) *DockerRun {
+	return r.Publish(ContainerPort(container).Localhost())
+}
+
+// PublishAll will publish every port exposed by the image to a random host port.
+func (r *DockerRun) PublishAll() *DockerRun {
+	r.publishAll = true
+	return r
+}
+
+// ProtocolPort returns the host port published for the given container port and [PortProtocol].
+func (c *Container) ProtocolPort(ctx context.Context, containerPort int, protocol PortProtocol) (int, error) {
+	if containerPort < 1 {
+		return 0, fmt.Errorf("invalid port value '%d'", containerPort)
+	}
+	if _, ok := knownProtocols[protocol]; !ok {
+		return 0, fmt.Errorf("unknown port protocol '%s'", protocol)
+	}
+	_, port, err := c.d.hostBinding(ctx, c.id, fmt.Sprintf("%d/%s", containerPort, protocol))
+	return port, err
+}
+
+// HostAddress returns the host address, like "127.0.0.1:49153", that can be used to connect to the given TCP container port.
+func (c *Container) HostAddress(ctx context.Context, containerPort int) (string, error) {
+	if containerPort < 1 {
+		return "", fmt.Errorf("invalid port value '%d'", containerPort)
+	}
+	host, port, err := c.d.hostBinding(ctx, c.id, strconv.Itoa(containerPort))
+	if err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), nil
+}
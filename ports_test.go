@@ -0,0 +1,60 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerPort_String(t *testing.T) {
+	tests := map[string]*DockerPort{
+		"8080":                     ContainerPort(8080),
+		"127.0.0.1::8080":          ContainerPort(8080).Localhost(),
+		"9090:8080":                ContainerPort(8080).HostPort(9090),
+		"127.0.0.1:9090:8080":      ContainerPort(8080).HostPort(9090).Localhost(),
+		"[::1]:9090:8080":          ContainerPort(8080).HostPort(9090).HostIP("::1"),
+		"53:53/udp":                ContainerPort(53).HostPort(53).UDP(),
+		"9000/sctp":                ContainerPort(9000).SCTP(),
+		"8000-8010:8080":           ContainerPort(8080).HostPortRange(8000, 8010),
+		"7000-7002:7000-7002/udp":  ContainerPortRange(7000, 7002).HostPortRange(7000, 7002).UDP(),
+		"127.0.0.1::7000-7002/udp": ContainerPortRange(7000, 7002).Localhost().UDP(),
+	}
+	for expected, port := range tests {
+		assert.Equal(t, expected, port.String())
+	}
+}
+
+func TestDockerPort_Invalid(t *testing.T) {
+	assert.Panics(t, func() { ContainerPort(0) })
+	assert.Panics(t, func() { ContainerPort(65536) })
+	assert.Panics(t, func() { ContainerPortRange(8010, 8000) })
+	assert.Panics(t, func() { ContainerPortRange(7000, 7002).HostPortRange(8000, 8010) })
+	assert.Panics(t, func() { ContainerPort(8080).HostIP("localhost") })
+	assert.Panics(t, func() { ContainerPort(8080).Protocol("http") })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").Publish(nil) })
+}
+
+func TestDockerRun_Run_Publish(t *testing.T) {
+	err := Docker().Dry().Run("some-image:latest").
+		PublishAll().
+		PublishPort(8080, 8081).
+		Publish(ContainerPort(53).UDP(), ContainerPort(5432).HostIP("0.0.0.0")).
+		PublishRandomPort(6379).
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run -P -p 8080:8081 -p 53/udp -p 0.0.0.0::5432 -p 127.0.0.1::6379 some-image:latest", err.Error())
+}
+
+func TestContainer_Ports(t *testing.T) {
+	ctx := context.Background()
+	c := Docker().Dry().Container("some-container")
+	_, err := c.Port(ctx, 8080)
+	assert.EqualError(t, err, "dry run: docker port some-container 8080")
+	_, err = c.ProtocolPort(ctx, 53, ProtocolUDP)
+	assert.EqualError(t, err, "dry run: docker port some-container 53/udp")
+	_, err = c.HostAddress(ctx, 8080)
+	assert.EqualError(t, err, "dry run: docker port some-container 8080")
+	_, err = c.ProtocolPort(ctx, 53, "http")
+	assert.EqualError(t, err, "unknown port protocol 'http'")
+}
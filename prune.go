@@ -0,0 +1,245 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+type pruneKind string
+
+const (
+	pruneImages     pruneKind = "image"
+	pruneContainers pruneKind = "container"
+	pruneVolumes    pruneKind = "volume"
+	pruneNetworks   pruneKind = "network"
+	pruneBuilder    pruneKind = "builder"
+	pruneSystem     pruneKind = "system"
+)
+
+// PruneImages creates a [DockerPrune] that removes dangling images, or all unused images with [DockerPrune.All].
+func (d *DockerRef) PruneImages() *DockerPrune {
+	return &DockerPrune{d: d, kind: pruneImages}
+}
+
+// PruneContainers creates a [DockerPrune] that removes all stopped containers.
+func (d *DockerRef) PruneContainers() *DockerPrune {
+	return &DockerPrune{d: d, kind: pruneContainers}
+}
+
+// PruneVolumes creates a [DockerPrune] that removes unused anonymous volumes, or all unused volumes with [DockerPrune.All].
+func (d *DockerRef) PruneVolumes() *DockerPrune {
+	return &DockerPrune{d: d, kind: pruneVolumes}
+}
+
+// PruneNetworks creates a [DockerPrune] that removes all unused networks.
+func (d *DockerRef) PruneNetworks() *DockerPrune {
+	return &DockerPrune{d: d, kind: pruneNetworks}
+}
+
+// PruneBuilder creates a [DockerPrune] that removes dangling build cache, or all build cache with [DockerPrune.All].
+func (d *DockerRef) PruneBuilder() *DockerPrune {
+	return &DockerPrune{d: d, kind: pruneBuilder}
+}
+
+// PruneSystem creates a [DockerPrune] that removes stopped containers, unused networks, dangling images, and dangling build cache.
+// Use [DockerPrune.All] to remove all unused images, and [DockerPrune.IncludeVolumes] to also remove unused volumes.
+func (d *DockerRef) PruneSystem() *DockerPrune {
+	return &DockerPrune{d: d, kind: pruneSystem}
+}
+
+// DockerPrune encapsulates a "docker prune" command for a type of object.
+// Pruning never prompts for confirmation.
+type DockerPrune struct {
+	d       *DockerRef
+	kind    pruneKind
+	all     bool
+	volumes bool
+	filters []string
+}
+
+// All will remove all unused objects, rather than only dangling objects.
+// This isn't supported for containers or networks.
+func (p *DockerPrune) All() *DockerPrune {
+	switch p.kind {
+	case pruneContainers, pruneNetworks:
+		panicf("%s prune doesn't support removing all objects", p.kind)
+		return p
+	}
+	p.all = true
+	return p
+}
+
+// IncludeVolumes will also remove unused volumes in a system prune.
+func (p *DockerPrune) IncludeVolumes() *DockerPrune {
+	if p.kind != pruneSystem {
+		panicf("%s prune doesn't support including volumes", p.kind)
+		return p
+	}
+	p.volumes = true
+	return p
+}
+
+// Until will only remove objects created more than the given duration ago.
+// This isn't supported for volumes.
+func (p *DockerPrune) Until(age time.Duration) *DockerPrune {
+	if age <= 0 {
+		panicf("invalid prune age '%s'", age)
+		return p
+	}
+	if p.kind == pruneVolumes {
+		panicf("%s prune doesn't support the until filter", p.kind)
+		return p
+	}
+	p.filters = append(p.filters, "until="+age.String())
+	return p
+}
+
+// Label will only remove objects with the given label key, and value if it's not empty.
+// This isn't supported for build cache.
+func (p *DockerPrune) Label(key, val string) *DockerPrune {
+	return p.labelFilter("label", key, val)
+}
+
+// NotLabel will only remove objects without the given label key, or value if it's not empty.
+// This isn't supported for build cache.
+func (p *DockerPrune) NotLabel(key, val string) *DockerPrune {
+	return p.labelFilter("label!", key, val)
+}
+
+func (p *DockerPrune) labelFilter(filter, key, val string) *DockerPrune {
+	anyBlankPanic(strmap{"key": &key})
+	if p.kind == pruneBuilder {
+		panicf("%s prune doesn't support label filters", p.kind)
+		return p
+	}
+	if len(val) > 0 {
+		p.filters = append(p.filters, fmt.Sprintf("%s=%s=%s", filter, key, val))
+	} else {
+		p.filters = append(p.filters, fmt.Sprintf("%s=%s", filter, key))
+	}
+	return p
+}
+
+func (p *DockerPrune) Task() Task {
+	return p.Run
+}
+
+func (p *DockerPrune) Run(ctx context.Context) error {
+	args := []string{string(p.kind), "prune", "-f"}
+	if p.all {
+		args = append(args, "-a")
+	}
+	if p.volumes {
+		args = append(args, "--volumes")
+	}
+	for _, filter := range p.filters {
+		args = append(args, "--filter", filter)
+	}
+	return p.d.Command(args...).Run(ctx)
+}
+
+// RetainImages creates a [DockerRetention], which removes all but the most recent tags of a repository.
+// This is useful to clean up old images produced by [DockerBuild], which are otherwise kept indefinitely.
+func (d *DockerRef) RetainImages(repository string, keep int) *DockerRetention {
+	anyBlankPanic(strmap{"repository": &repository})
+	if keep < 0 {
+		panicf("invalid number of tags to keep '%d'", keep)
+		return nil
+	}
+	return &DockerRetention{d: d, repository: repository, keep: keep}
+}
+
+// DockerRetention is a retention policy that keeps only the most recently created tags of a repository.
+type DockerRetention struct {
+	d          *DockerRef
+	repository string
+	keep       int
+	labels     []string
+	force      bool
+}
+
+// Label will only consider images with the given label key, and value if it's not empty.
+// This can be used with [DockerBuild.Label] to limit the policy to images built by a specific project.
+func (r *DockerRetention) Label(key, val string) *DockerRetention {
+	anyBlankPanic(strmap{"key": &key})
+	if len(val) > 0 {
+		key += "=" + val
+	}
+	r.labels = append(r.labels, key)
+	return r
+}
+
+// Force will force removal of images, even if they're used by a stopped container.
+func (r *DockerRetention) Force() *DockerRetention {
+	r.force = true
+	return r
+}
+
+func (r *DockerRetention) Task() Task {
+	return r.Run
+}
+
+func (r *DockerRetention) Run(ctx context.Context) error {
+	list := r.d.Images().Reference(r.repository)
+	for _, label := range r.labels {
+		list.filter("label", label)
+	}
+	images, err := list.List(ctx)
+	if err != nil {
+		return err
+	}
+	expired, err := expiredTags(images, r.keep)
+	if err != nil || len(expired) == 0 {
+		return err
+	}
+	rmi := &DockerRemoveImage{d: r.d, images: expired, force: r.force}
+	return rmi.Run(ctx)
+}
+
+// imageCreatedLayout is the format of [ImageSummary.CreatedAt].
+const imageCreatedLayout = "2006-01-02 15:04:05 -0700 MST"
+
+// expiredTags returns the references of all tagged images except for the keep most recently created.
+// Tags of the same image ID count as a single image, so either all or none of its tags are returned.
+func expiredTags(images []*ImageSummary, keep int) ([]string, error) {
+	type taggedImage struct {
+		refs    []string
+		created time.Time
+	}
+	var tagged []*taggedImage
+	byID := map[string]*taggedImage{}
+	for _, img := range images {
+		if img.Tag == "<none>" || len(img.Tag) == 0 {
+			continue
+		}
+		ref := img.Repository + ":" + img.Tag
+		if existing, ok := byID[img.ID]; ok {
+			existing.refs = append(existing.refs, ref)
+			continue
+		}
+		created, err := time.Parse(imageCreatedLayout, img.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse creation time of '%s': %w", ref, err)
+		}
+		entry := &taggedImage{refs: []string{ref}, created: created}
+		if len(img.ID) > 0 {
+			byID[img.ID] = entry
+		}
+		tagged = append(tagged, entry)
+	}
+	if len(tagged) <= keep {
+		return nil, nil
+	}
+	sort.SliceStable(tagged, func(i, j int) bool {
+		return tagged[i].created.After(tagged[j].created)
+	})
+	var expired []string
+	for _, img := range tagged[keep:] {
+		expired = append(expired, img.refs...)
+	}
+	return expired, nil
+}
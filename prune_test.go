@@ -0,0 +1,76 @@
+package modmake_docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerPrune(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.PruneImages().Task(), "docker image prune -f")
+	isDryRunResult(t, d.PruneImages().All().Until(24*time.Hour).Label("project", "test").Task(),
+		"docker image prune -f -a --filter until=24h0m0s --filter label=project=test")
+	isDryRunResult(t, d.PruneContainers().NotLabel("keep", "").Task(),
+		"docker container prune -f --filter label!=keep")
+	isDryRunResult(t, d.PruneVolumes().All().Label("project", "").Task(),
+		"docker volume prune -f -a --filter label=project")
+	isDryRunResult(t, d.PruneNetworks().Until(time.Hour).Task(),
+		"docker network prune -f --filter until=1h0m0s")
+	isDryRunResult(t, d.PruneBuilder().All().Until(72*time.Hour).Task(),
+		"docker builder prune -f -a --filter until=72h0m0s")
+	isDryRunResult(t, d.PruneSystem().All().IncludeVolumes().Task(),
+		"docker system prune -f -a --volumes")
+}
+
+func TestDockerPrune_Unsupported(t *testing.T) {
+	d := Docker().Dry()
+	assert.Panics(t, func() { d.PruneContainers().All() })
+	assert.Panics(t, func() { d.PruneImages().IncludeVolumes() })
+	assert.Panics(t, func() { d.PruneVolumes().Until(time.Hour) })
+	assert.Panics(t, func() { d.PruneBuilder().Label("project", "test") })
+}
+
+func TestDockerRetention(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.RetainImages("my-image", 3).Label("project", "test").Task(),
+		"docker images --no-trunc --filter reference=my-image --filter label=project=test --format={{json .}}")
+}
+
+func TestExpiredTags(t *testing.T) {
+	images := []*ImageSummary{
+		{Repository: "my-image", Tag: "2", CreatedAt: "2024-03-02 12:00:00 +0000 UTC"},
+		{Repository: "my-image", Tag: "<none>", CreatedAt: "2024-03-02 11:00:00 +0000 UTC"},
+		{Repository: "my-image", Tag: "3", CreatedAt: "2024-03-03 12:00:00 +0000 UTC"},
+		{Repository: "my-image", Tag: "1", CreatedAt: "2024-03-01 07:00:00 -0500 EST"},
+		{Repository: "my-image", Tag: "0", CreatedAt: "2024-02-28 12:00:00 +0000 UTC"},
+	}
+	expired, err := expiredTags(images, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my-image:1", "my-image:0"}, expired)
+
+	expired, err = expiredTags(images, 4)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	_, err = expiredTags([]*ImageSummary{{Repository: "my-image", Tag: "1", CreatedAt: "yesterday"}}, 0)
+	assert.Error(t, err)
+}
+
+func TestExpiredTags_SharedID(t *testing.T) {
+	images := []*ImageSummary{
+		{ID: "sha256:ccc", Repository: "my-image", Tag: "latest", CreatedAt: "2024-03-03 12:00:00 +0000 UTC"},
+		{ID: "sha256:ccc", Repository: "my-image", Tag: "3", CreatedAt: "2024-03-03 12:00:00 +0000 UTC"},
+		{ID: "sha256:bbb", Repository: "my-image", Tag: "2", CreatedAt: "2024-03-02 12:00:00 +0000 UTC"},
+		{ID: "sha256:aaa", Repository: "my-image", Tag: "1", CreatedAt: "2024-03-01 12:00:00 +0000 UTC"},
+		{ID: "sha256:aaa", Repository: "my-image", Tag: "stable", CreatedAt: "2024-03-01 12:00:00 +0000 UTC"},
+	}
+	expired, err := expiredTags(images, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"my-image:1", "my-image:stable"}, expired)
+
+	expired, err = expiredTags(images, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+}
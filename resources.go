@@ -0,0 +1,209 @@
+package modmake_docker
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes, used for memory and storage limits.
+// It's formatted using the binary units understood by Docker, like "512m" or "2g".
+type ByteSize int64
+
+const (
+	Byte ByteSize = 1
+	KiB           = 1024 * Byte
+	MiB           = 1024 * KiB
+	GiB           = 1024 * MiB
+	TiB           = 1024 * GiB
+
+	// Unlimited can be used with [DockerRun.MemorySwap] to allow unlimited swap.
+	Unlimited ByteSize = -1
+)
+
+var byteSizeUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"t", TiB},
+	{"g", GiB},
+	{"m", MiB},
+	{"k", KiB},
+}
+
+var byteSizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?) ?(?:([kKmMgGtT])[iI]?)?[bB]?$`)
+
+// ParseByteSize parses a size like "512m", "2GiB", "1.5g", or "1024".
+// Units are always binary, so "1k" is 1024 bytes.
+func ParseByteSize(size string) (ByteSize, error) {
+	match := byteSizePattern.FindStringSubmatch(strings.TrimSpace(size))
+	if match == nil {
+		return 0, fmt.Errorf("invalid byte size '%s'", size)
+	}
+	val, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size '%s': %w", size, err)
+	}
+	unit := Byte
+	if len(match[2]) > 0 {
+		suffix := strings.ToLower(match[2])
+		for _, u := range byteSizeUnits {
+			if u.suffix == suffix {
+				unit = u.size
+				break
+			}
+		}
+	}
+	bytes := val * float64(unit)
+	// MaxInt64 rounds up to 2^63 as a float64, so it's out of range too.
+	if bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte size '%s' is too large", size)
+	}
+	return ByteSize(bytes), nil
+}
+
+// MustParseByteSize is like [ParseByteSize], but panics if the size is invalid.
+func MustParseByteSize(size string) ByteSize {
+	parsed, err := ParseByteSize(size)
+	if err != nil {
+		panic(err.Error())
+	}
+	return parsed
+}
+
+// String formats the size using the largest unit that represents it exactly.
+func (b ByteSize) String() string {
+	if b <= 0 {
+		return strconv.FormatInt(int64(b), 10)
+	}
+	for _, u := range byteSizeUnits {
+		if b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "b"
+}
+
+var knownUlimits = map[string]struct{}{
+	"core":       {},
+	"cpu":        {},
+	"data":       {},
+	"fsize":      {},
+	"locks":      {},
+	"memlock":    {},
+	"msgqueue":   {},
+	"nice":       {},
+	"nofile":     {},
+	"nproc":      {},
+	"rss":        {},
+	"rtprio":     {},
+	"rttime":     {},
+	"sigpending": {},
+	"stack":      {},
+}
+
+func cpusArg(cpus float64) string {
+	if cpus <= 0 {
+		panicf("invalid CPUs '%f'", cpus)
+	}
+	return "--cpus=" + strconv.FormatFloat(cpus, 'f', -1, 64)
+}
+
+func cpuSharesArg(shares int) string {
+	if shares < 2 {
+		panicf("invalid CPU shares '%d'", shares)
+	}
+	return "--cpu-shares=" + strconv.Itoa(shares)
+}
+
+func cpuSetArg(cpus string) string {
+	anyBlankPanic(strmap{"cpus": &cpus})
+	return "--cpuset-cpus=" + cpus
+}
+
+func memoryArg(size ByteSize) string {
+	if size < 1 {
+		panicf("invalid memory limit '%s'", size)
+	}
+	return "--memory=" + size.String()
+}
+
+func memorySwapArg(size ByteSize) string {
+	if size < Unlimited || size == 0 {
+		panicf("invalid memory swap limit '%s'", size)
+	}
+	return "--memory-swap=" + size.String()
+}
+
+func pidsLimitArg(limit int) string {
+	if limit < -1 || limit == 0 {
+		panicf("invalid pids limit '%d'", limit)
+	}
+	return "--pids-limit=" + strconv.Itoa(limit)
+}
+
+// Memory limits the memory the container may use.
+func (r *DockerRun) Memory(size ByteSize) *DockerRun {
+	r.resources = append(r.resources, memoryArg(size))
+	return r
+}
+
+// MemorySwap limits the total of memory and swap the container may use.
+// Use [Unlimited] to allow unlimited swap.
+func (r *DockerRun) MemorySwap(size ByteSize) *DockerRun {
+	r.resources = append(r.resources, memorySwapArg(size))
+	return r
+}
+
+// CPUs limits the number of CPUs the container may use, like 1.5.
+func (r *DockerRun) CPUs(cpus float64) *DockerRun {
+	r.resources = append(r.resources, cpusArg(cpus))
+	return r
+}
+
+// CPUShares sets the relative CPU weight of the container, where the default is 1024.
+func (r *DockerRun) CPUShares(shares int) *DockerRun {
+	r.resources = append(r.resources, cpuSharesArg(shares))
+	return r
+}
+
+// CPUSetCPUs restricts the CPUs the container may run on, like "0-3" or "0,1".
+func (r *DockerRun) CPUSetCPUs(cpus string) *DockerRun {
+	r.resources = append(r.resources, cpuSetArg(cpus))
+	return r
+}
+
+// PidsLimit limits the number of processes in the container.
+// A value of -1 allows unlimited processes.
+func (r *DockerRun) PidsLimit(limit int) *DockerRun {
+	r.resources = append(r.resources, pidsLimitArg(limit))
+	return r
+}
+
+// Ulimit sets a resource limit in the container, like "nofile".
+// A value of -1 is unlimited.
+func (r *DockerRun) Ulimit(name string, soft, hard int64) *DockerRun {
+	anyBlankPanic(strmap{"name": &name})
+	if _, ok := knownUlimits[name]; !ok {
+		panicf("unknown ulimit '%s'", name)
+		return r
+	}
+	if soft < -1 || hard < -1 || (hard >= 0 && (soft < 0 || soft > hard)) {
+		panicf("invalid ulimit '%s=%d:%d'", name, soft, hard)
+		return r
+	}
+	r.resources = append(r.resources, fmt.Sprintf("--ulimit=%s=%d:%d", name, soft, hard))
+	return r
+}
+
+// ShmSize sets the size of /dev/shm in the container.
+func (r *DockerRun) ShmSize(size ByteSize) *DockerRun {
+	if size < 1 {
+		panicf("invalid shm size '%s'", size)
+		return r
+	}
+	r.resources = append(r.resources, "--shm-size="+size.String())
+	return r
+}
@@ -0,0 +1,77 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]ByteSize{
+		"1024":   1024,
+		"100b":   100,
+		"1k":     KiB,
+		"512m":   512 * MiB,
+		"512MB":  512 * MiB,
+		"2g":     2 * GiB,
+		"2GiB":   2 * GiB,
+		"2Gi":    2 * GiB,
+		"1.5g":   1536 * MiB,
+		"1 t":    TiB,
+		" 64m  ": 64 * MiB,
+		"8191t":  8191 * TiB,
+	}
+	for input, expected := range tests {
+		t.Run(input, func(t *testing.T) {
+			size, err := ParseByteSize(input)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, size)
+		})
+	}
+
+	for _, input := range []string{"", "m", "-1m", "12x", "1.2.3g", "1i", "1ib", "5iB", "1 i", "99999999999t", "9223372036854775807", "8388608t"} {
+		_, err := ParseByteSize(input)
+		assert.Error(t, err, "input '%s' should be invalid", input)
+	}
+	assert.Panics(t, func() { MustParseByteSize("lots") })
+}
+
+func TestByteSize_String(t *testing.T) {
+	assert.Equal(t, "512m", (512 * MiB).String())
+	assert.Equal(t, "2g", (2 * GiB).String())
+	assert.Equal(t, "1536m", MustParseByteSize("1.5g").String())
+	assert.Equal(t, "1k", KiB.String())
+	assert.Equal(t, "100b", ByteSize(100).String())
+	assert.Equal(t, "-1", Unlimited.String())
+}
+
+func TestDockerRun_Run_Resources(t *testing.T) {
+	err := Docker().Dry().Run("some-image:latest").
+		Memory(512*MiB).
+		MemorySwap(GiB).
+		CPUs(1.5).
+		CPUShares(512).
+		CPUSetCPUs("0,1").
+		PidsLimit(100).
+		Ulimit("nofile", 1024, 2048).
+		Ulimit("core", -1, -1).
+		ShmSize(64 * MiB).
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --memory=512m --memory-swap=1g --cpus=1.5 --cpu-shares=512 --cpuset-cpus=0,1 --pids-limit=100 --ulimit=nofile=1024:2048 --ulimit=core=-1:-1 --shm-size=64m some-image:latest", err.Error())
+}
+
+func TestDockerRun_Resources_Invalid(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest")
+	assert.Panics(t, func() { run.Memory(0) })
+	assert.Panics(t, func() { run.MemorySwap(0) })
+	assert.Panics(t, func() { run.CPUs(0) })
+	assert.Panics(t, func() { run.CPUShares(1) })
+	assert.Panics(t, func() { run.CPUSetCPUs("") })
+	assert.Panics(t, func() { run.PidsLimit(0) })
+	assert.Panics(t, func() { run.Ulimit("files", 1, 2) })
+	assert.Panics(t, func() { run.Ulimit("nofile", 2048, 1024) })
+	assert.Panics(t, func() { run.Ulimit("nofile", -1, 1024) })
+	assert.Panics(t, func() { run.ShmSize(-1) })
+}
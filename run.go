@@ -1,13 +1,21 @@
 package modmake_docker
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	. "github.com/saylorsolutions/modmake"
 )
 
+const defaultCancelGrace = 10 * time.Second
+
 // Run creates a new [DockerRun] instance, used to run a container.
 func (d *DockerRef) Run(image string, args ...string) *DockerRun {
 	anyBlankPanic(strmap{"image": &image})
@@ -16,6 +24,8 @@ func (d *DockerRef) Run(image string, args ...string) *DockerRun {
 		image:         image,
 		args:          args,
 		restartPolicy: RestartNever,
+		cancelGrace:   defaultCancelGrace,
+		stopTimeout:   -1,
 	}
 	return r
 }
@@ -30,33 +40,88 @@ type DockerRun struct {
 	hostname        string
 	workingDir      string
 	networkConn     string
+	networkAliases  []string
+	extraNetworks   []networkEndpoint
+	ipv4            string
+	ipv6            string
+	macAddress      string
+	extraHosts      []string
+	dns             []string
 	detached        bool
 	interactive     bool
 	privileged      bool
+	hardened        bool
 	readOnly        bool
 	removeAfterExit bool
 	restartPolicy   RestartPolicy
 	env             []string
+	envFiles        []string
+	secretEnv       []string
 	portMappings    []string
+	publishAll      bool
 	bindMounts      []string
+	mounts          []string
+	resources       []string
+	user            string
+	security        []string
+	entrypoint      []string
+	init            bool
+	stopSignal      string
+	stopTimeout     time.Duration
+	pidMode         string
+	ipcMode         string
+	platform        string
+	labels          []string
+	labelFiles      []string
+	healthCmd       string
+	healthOpts      []string
+	noHealthCheck   bool
+	logDriver       LoggingDriver
+	logOpts         []string
+	stdin           io.Reader
+	stdout          io.Writer
+	stderr          io.Writer
+	waits           []WaitStrategy
+	cancelGrace     time.Duration
+	removeOnCancel  bool
 }
 
 // Detached runs the container detached, printing the container ID instead of writing logs to STDOUT.
+// Use [DockerRun.Start] to get a [Container] handle for the detached container instead.
+// This panics if any stream was supplied with [DockerRun.Stdin], [DockerRun.Stdout], or [DockerRun.Stderr].
 func (r *DockerRun) Detached() *DockerRun {
+	r.detachedStreamPanic()
 	r.detached = true
 	return r
 }
 
+// WaitFor will wait for the container to be ready after it's started, according to each [WaitStrategy] in order.
+// This implies [DockerRun.Detached], since a container running in the foreground has exited by the time Run returns.
+// If the container isn't ready, then Run stops it as described in [DockerRun.CancelGracePeriod] and [DockerRun.RemoveOnCancel].
+// Use [DockerRun.Start] to keep the container for inspection instead.
+func (r *DockerRun) WaitFor(strategies ...WaitStrategy) *DockerRun {
+	for _, strategy := range strategies {
+		if strategy == nil {
+			panic("nil wait strategy")
+		}
+	}
+	r.detachedStreamPanic()
+	r.detached = true
+	r.waits = append(r.waits, strategies...)
+	return r
+}
+
 // SetEnvVar will set an environment variable in the container when it's run.
+// The value is passed as-is, and may be empty.
+// Use [DockerRun.SetSecretEnvVar] for values that shouldn't appear on the command line.
 func (r *DockerRun) SetEnvVar(key, val string) *DockerRun {
-	anyBlankPanic(strmap{"key": &key, "val": &val})
-	_val := fmt.Sprintf("%s=%s", strings.TrimSpace(key), strings.TrimSpace(val))
-	r.env = append(r.env, _val)
+	r.env = append(r.env, validEnvKey(key)+"="+val)
 	return r
 }
 
 // PublishPort will publish a port, mapping a host port to a container port.
 // These ports don't have to match.
+// Use [DockerRun.Publish] for other protocols, host IPs, port ranges, or random host ports.
 func (r *DockerRun) PublishPort(host, container int) *DockerRun {
 	if host < 1 || container < 1 {
 		panicf("invalid port value '%d:%d'", host, container)
@@ -77,6 +142,9 @@ func (r *DockerRun) SetHostname(host string) *DockerRun {
 
 // InteractiveTerminal will allow the specified command to be interactive, allocating a terminal to do so.
 func (r *DockerRun) InteractiveTerminal() *DockerRun {
+	if r.stdin != nil {
+		panic("an interactive terminal cannot be used with supplied stdin")
+	}
 	r.interactive = true
 	return r
 }
@@ -88,16 +156,14 @@ func (r *DockerRun) Name(name string) *DockerRun {
 	return r
 }
 
-// ConnectNetwork will allow this container to communicate using the named network.
-func (r *DockerRun) ConnectNetwork(network string) *DockerRun {
-	anyBlankPanic(strmap{"network": &network})
-	r.networkConn = network
-	return r
-}
-
 // PrivilegedContainer will run this container in "privileged" mode.
 // This should not be used unless specifically required by an image constraint, as it opens up the container host to possible security vulnerabilities.
+// Prefer adding only the capabilities needed with [DockerRun.CapAdd].
+// This panics if the container was hardened with [DockerRun.Hardened].
 func (r *DockerRun) PrivilegedContainer() *DockerRun {
+	if r.hardened {
+		panic("a hardened container cannot be privileged")
+	}
 	r.privileged = true
 	return r
 }
@@ -108,6 +174,98 @@ func (r *DockerRun) ReadOnlyFS() *DockerRun {
 	return r
 }
 
+// Entrypoint replaces the image's entrypoint with the given command and arguments.
+// The arguments are passed to the entrypoint ahead of any arguments given to [DockerRef.Run], so they're never parsed by a shell.
+func (r *DockerRun) Entrypoint(cmd string, args ...string) *DockerRun {
+	anyBlankPanic(strmap{"cmd": &cmd})
+	r.entrypoint = append([]string{cmd}, args...)
+	return r
+}
+
+// Init runs an init process in the container that forwards signals and reaps zombie processes.
+// This is useful for images with an entrypoint that doesn't handle signals, like a shell script.
+func (r *DockerRun) Init() *DockerRun {
+	r.init = true
+	return r
+}
+
+// StopSignal sets the signal sent to the container to stop it, like "SIGINT".
+// The default is the signal configured by the image, which is usually SIGTERM.
+func (r *DockerRun) StopSignal(signal string) *DockerRun {
+	anyBlankPanic(strmap{"signal": &signal})
+	r.stopSignal = signal
+	return r
+}
+
+// StopTimeout sets how long the container has to stop before it's killed, when stopped without an explicit timeout.
+// The value is rounded to whole seconds.
+func (r *DockerRun) StopTimeout(timeout time.Duration) *DockerRun {
+	if timeout < 0 {
+		panicf("invalid stop timeout '%s'", timeout)
+		return r
+	}
+	r.stopTimeout = timeout
+	return r
+}
+
+var knownPIDModes = map[string]struct{}{
+	"host": {},
+}
+
+var knownIPCModes = map[string]struct{}{
+	"none":      {},
+	"private":   {},
+	"shareable": {},
+	"host":      {},
+}
+
+// namespaceMode validates a namespace mode, which is either a known mode or "container:<name or ID>".
+func namespaceMode(kind, mode string, known map[string]struct{}) string {
+	anyBlankPanic(strmap{"mode": &mode})
+	if container, ok := strings.CutPrefix(mode, "container:"); ok {
+		if len(strings.TrimSpace(container)) == 0 {
+			panicf("missing container in %s mode '%s'", kind, mode)
+		}
+		return mode
+	}
+	if _, ok := known[mode]; !ok {
+		panicf("unknown %s mode '%s'", kind, mode)
+	}
+	return mode
+}
+
+// PIDMode sets the PID namespace of the container.
+// This may be "host", or "container:<name or ID>" to share the PID namespace of another container.
+func (r *DockerRun) PIDMode(mode string) *DockerRun {
+	r.pidMode = namespaceMode("pid", mode, knownPIDModes)
+	return r
+}
+
+// IPCMode sets the IPC namespace of the container.
+// This may be "none", "private", "shareable", "host", or "container:<name or ID>" to share the IPC namespace of another container.
+func (r *DockerRun) IPCMode(mode string) *DockerRun {
+	r.ipcMode = namespaceMode("ipc", mode, knownIPCModes)
+	return r
+}
+
+// Platform sets the platform of the image to run, like "linux/amd64" or "linux/arm64/v8".
+func (r *DockerRun) Platform(platform string) *DockerRun {
+	anyBlankPanic(strmap{"platform": &platform})
+	parts := strings.Split(platform, "/")
+	if len(parts) > 3 {
+		panicf("invalid platform '%s'", platform)
+		return r
+	}
+	for _, part := range parts {
+		if len(strings.TrimSpace(part)) == 0 {
+			panicf("invalid platform '%s'", platform)
+			return r
+		}
+	}
+	r.platform = platform
+	return r
+}
+
 // RestartPolicy is used with DockerRun to establish a restart policy for a given container.
 type RestartPolicy string
 
@@ -152,6 +310,25 @@ func (r *DockerRun) SetRestartRetries(retries int) *DockerRun {
 	return r
 }
 
+// CancelGracePeriod sets how long a container is given to stop when the context is cancelled while it's running in the foreground, or being started.
+// If the container hasn't stopped by then, it's killed.
+// The default is 10 seconds, which matches the Docker default.
+func (r *DockerRun) CancelGracePeriod(grace time.Duration) *DockerRun {
+	if grace < 0 {
+		panicf("invalid grace period '%s'", grace)
+		return r
+	}
+	r.cancelGrace = grace
+	return r
+}
+
+// RemoveOnCancel will remove the container after it's stopped due to context cancellation, or because it wasn't ready according to [DockerRun.WaitFor].
+// This has no effect with [DockerRun.RemoveAfterExit], since Docker will remove the container.
+func (r *DockerRun) RemoveOnCancel() *DockerRun {
+	r.removeOnCancel = true
+	return r
+}
+
 // RemoveAfterExit will cause the container to be removed when it's stopped.
 // This is mutually exclusive with a [RestartPolicy].
 func (r *DockerRun) RemoveAfterExit() *DockerRun {
@@ -161,6 +338,7 @@ func (r *DockerRun) RemoveAfterExit() *DockerRun {
 }
 
 // VolumeMount will mount a host path at a container path to allow reading/writing in the host file system.
+// Use [DockerRun.Mount] for named volumes, tmpfs mounts, or more bind mount options.
 func (r *DockerRun) VolumeMount(hostPath, containerPath PathString) *DockerRun {
 	cps := containerPath.String()
 	anyBlankPanic(strmap{"containerPath": &cps})
@@ -173,6 +351,18 @@ func (r *DockerRun) VolumeMount(hostPath, containerPath PathString) *DockerRun {
 	return r
 }
 
+// Mount will mount each [DockerMount] in the container.
+// See [MountBind], [MountVolume], and [MountTmpfs].
+func (r *DockerRun) Mount(mounts ...*DockerMount) *DockerRun {
+	for _, m := range mounts {
+		if m == nil {
+			panic("nil mount")
+		}
+		r.mounts = append(r.mounts, m.String())
+	}
+	return r
+}
+
 // WorkingDirectory will set the working directory for the container entry point command.
 func (r *DockerRun) WorkingDirectory(containerPath PathString) *DockerRun {
 	r.workingDir = containerPath.ToSlash()
@@ -184,6 +374,236 @@ func (r *DockerRun) Task() Task {
 }
 
 func (r *DockerRun) Run(ctx context.Context) error {
+	if r.detached {
+		if err := r.detachedStreamErr(); err != nil {
+			return err
+		}
+	}
+	if r.detached && len(r.waits) > 0 {
+		c, err := r.Start(ctx)
+		if c != nil && err != nil && ctx.Err() == nil {
+			// There's no handle to return, so the container is stopped instead of being left running, as if it were cancelled.
+			return errors.Join(err, r.cleanupCancelled(c.id))
+		}
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	if r.detached {
+		return r.runCLI(ctx, true, nil, nil, nil)
+	}
+	stdin := r.stdin
+	if stdin == nil {
+		stdin = os.Stdin
+	}
+	return r.runCLI(ctx, false, stdin, r.stdout, r.stderr)
+}
+
+// Start runs the container detached, and returns a [Container] handle for it once it's ready according to [DockerRun.WaitFor].
+// If the container was created but wasn't ready, then both the handle and an error are returned.
+// The handle is valid in that case, and the caller is responsible for removing the container, like with [Container.Remove].
+// If the context is cancelled before the container is ready, then it's stopped as described in [DockerRun.CancelGracePeriod].
+// Streams can't be supplied to a detached container, so an error is returned if any were supplied.
+func (r *DockerRun) Start(ctx context.Context) (*Container, error) {
+	if err := r.detachedStreamErr(); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+	var stdout, stderr bytes.Buffer
+	args := r.cliArgs(true, "")
+	if err := r.runCLI(ctx, true, nil, &stdout, &stderr); err != nil {
+		return nil, commandErr(args, err, stderr.String())
+	}
+	c := &Container{d: r.d, id: strings.TrimSpace(stdout.String()), name: r.name}
+	if err := r.awaitStarted(ctx, c); err != nil {
+		if ctx.Err() != nil {
+			return c, errors.Join(err, r.cleanupCancelled(c.id))
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+func (r *DockerRun) awaitStarted(ctx context.Context, c *Container) error {
+	if len(c.name) == 0 {
+		name, err := r.d.output(ctx, "inspect", "-f", "{{.Name}}", c.id)
+		if err != nil {
+			return err
+		}
+		c.name = strings.TrimPrefix(name, "/")
+	}
+	return waitAll(ctx, r.d, c.id, r.waits)
+}
+
+// runCLI runs "docker run", cleaning up the container it creates if the context is cancelled before the CLI exits.
+// The CLI itself isn't tied to the context, because killing it would leave the container running.
+// If there are additional networks to connect, then the container is created, connected, and started in separate steps instead.
+func (r *DockerRun) runCLI(ctx context.Context, detached bool, stdin io.Reader, stdout, stderr io.Writer) error {
+	if r.d.dryRun {
+		return r.d.commandIO(stdin, stdout, stderr, r.cliArgs(detached, "")...).Run(ctx)
+	}
+	cancellable := ctx.Done() != nil
+	var tmpDir string
+	if len(r.secretEnv) > 0 || cancellable {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "modmake-docker-")
+		if err != nil {
+			return fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		defer func() {
+			_ = os.RemoveAll(tmpDir)
+		}()
+	}
+	var secretFile string
+	if len(r.secretEnv) > 0 {
+		var err error
+		secretFile, err = r.writeSecretEnv(tmpDir)
+		if err != nil {
+			return err
+		}
+	}
+	if len(r.extraNetworks) > 0 {
+		return r.createAndStart(ctx, detached, secretFile, stdin, stdout, stderr)
+	}
+	args := r.runArgs(detached, secretFile)
+	if !cancellable {
+		return r.d.commandIO(stdin, stdout, stderr, args...).Run(ctx)
+	}
+	// The container doesn't exist until Docker writes its ID to the cidfile, which may take a while if the image is pulled first.
+	// This is the case even for a named container, so cleanup waits for the ID.
+	cidFile := filepath.Join(tmpDir, "cid")
+	args = append([]string{args[0], "--cidfile=" + cidFile}, args[1:]...)
+	containerRef := func() string {
+		cid, err := os.ReadFile(cidFile)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(cid))
+	}
+	return r.runCancellable(ctx, args, containerRef, stdin, stdout, stderr)
+}
+
+// createAndStart creates the container, connects it to each additional network, then starts it.
+// This ensures that the container process never runs without all of its networks.
+func (r *DockerRun) createAndStart(ctx context.Context, detached bool, secretFile string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var idBuf bytes.Buffer
+	if err := r.d.commandIO(nil, &idBuf, stderr, r.createArgs(secretFile)...).Run(ctx); err != nil {
+		return err
+	}
+	id := strings.TrimSpace(idBuf.String())
+	removeCreated := func(err error) error {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), defaultCleanupTimeout)
+		defer cancel()
+		if _, rmErr := r.d.output(cleanupCtx, "rm", "-f", id); rmErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to remove container '%s': %w", id, rmErr))
+		}
+		return err
+	}
+	for _, endpoint := range r.extraNetworks {
+		if err := r.d.commandIO(nil, io.Discard, stderr, endpoint.connectArgs(id)...).Run(ctx); err != nil {
+			return removeCreated(err)
+		}
+	}
+
+	if detached {
+		if err := r.d.commandIO(nil, io.Discard, stderr, "start", id).Run(ctx); err != nil {
+			return removeCreated(err)
+		}
+		if stdout == nil {
+			stdout = os.Stdout
+		}
+		_, err := io.WriteString(stdout, id+"\n")
+		return err
+	}
+	args := []string{"start", "-a"}
+	if r.interactive || r.stdin != nil {
+		args = append(args, "-i")
+	}
+	args = append(args, id)
+	if ctx.Done() == nil {
+		return r.d.commandIO(stdin, stdout, stderr, args...).Run(ctx)
+	}
+	return r.runCancellable(ctx, args, func() string { return id }, stdin, stdout, stderr)
+}
+
+// runCancellable runs a Docker command that runs a container in the foreground, cleaning up the container if the context is cancelled.
+// containerRef returns the name or ID of the container, or an empty string if it isn't known yet.
+func (r *DockerRun) runCancellable(ctx context.Context, args []string, containerRef func() string, stdin io.Reader, stdout, stderr io.Writer) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- r.d.commandIO(stdin, stdout, stderr, args...).Run(context.Background())
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	// The container may not have been created yet, so wait for its ID to be known, or for the CLI to exit.
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	ref := containerRef()
+	for len(ref) == 0 {
+		select {
+		case <-done:
+			if ref = containerRef(); len(ref) == 0 {
+				return ctx.Err()
+			}
+			return errors.Join(ctx.Err(), r.cleanupCancelled(ref))
+		case <-ticker.C:
+			ref = containerRef()
+		}
+	}
+	err := r.cleanupCancelled(ref)
+	select {
+	case <-done:
+	case <-time.After(defaultCleanupTimeout):
+	}
+	return errors.Join(ctx.Err(), err)
+}
+
+// cleanupCancelled stops a container after its context has been cancelled, or it wasn't ready, killing it if it doesn't stop within the grace period.
+// The container is also removed if [DockerRun.RemoveOnCancel] was used.
+func (r *DockerRun) cleanupCancelled(ref string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cancelGrace+defaultCleanupTimeout)
+	defer cancel()
+	var errs []error
+	if _, err := r.d.output(ctx, "stop", "-t", durationSeconds(r.cancelGrace), ref); err != nil {
+		if _, killErr := r.d.output(ctx, "kill", ref); killErr != nil {
+			errs = append(errs, fmt.Errorf("failed to stop container '%s' after cancellation: %w", ref, errors.Join(err, killErr)))
+		}
+	}
+	if r.removeOnCancel && !r.removeAfterExit {
+		if _, err := r.d.output(ctx, "rm", "-f", ref); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove container '%s' after cancellation: %w", ref, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cliArgs returns the arguments of the first command run by runCLI.
+func (r *DockerRun) cliArgs(detached bool, secretFile string) []string {
+	if len(r.extraNetworks) > 0 {
+		return r.createArgs(secretFile)
+	}
+	return r.runArgs(detached, secretFile)
+}
+
+// createArgs returns the arguments to create the container without starting it.
+func (r *DockerRun) createArgs(secretFile string) []string {
+	args := r.runArgs(false, secretFile)
+	args[0] = "create"
+	return args
+}
+
+func (r *DockerRun) runArgs(detached bool, secretFile string) []string {
 	args := []string{"run"}
 	if len(r.name) > 0 {
 		args = append(args, "--name="+r.name)
@@ -194,11 +614,13 @@ func (r *DockerRun) Run(ctx context.Context) error {
 	if len(r.workingDir) > 0 {
 		args = append(args, "-w", r.workingDir)
 	}
-	if r.detached {
+	if detached {
 		args = append(args, "-d")
 	}
 	if r.interactive {
 		args = append(args, "-it")
+	} else if r.stdin != nil && !detached {
+		args = append(args, "-i")
 	}
 	if r.privileged {
 		args = append(args, "--privileged")
@@ -213,12 +635,69 @@ func (r *DockerRun) Run(ctx context.Context) error {
 	}
 	if len(r.networkConn) > 0 {
 		args = append(args, "--network="+r.networkConn)
+		for _, alias := range r.networkAliases {
+			args = append(args, "--network-alias="+alias)
+		}
+		if len(r.ipv4) > 0 {
+			args = append(args, "--ip="+r.ipv4)
+		}
+		if len(r.ipv6) > 0 {
+			args = append(args, "--ip6="+r.ipv6)
+		}
+	}
+	if len(r.macAddress) > 0 {
+		args = append(args, "--mac-address="+r.macAddress)
+	}
+	for _, host := range r.extraHosts {
+		args = append(args, "--add-host="+host)
+	}
+	args = append(args, r.dns...)
+	args = append(args, r.resources...)
+	if len(r.user) > 0 {
+		args = append(args, "-u", r.user)
+	}
+	args = append(args, r.security...)
+	if len(r.entrypoint) > 0 {
+		args = append(args, "--entrypoint="+r.entrypoint[0])
+	}
+	if r.init {
+		args = append(args, "--init")
+	}
+	if len(r.stopSignal) > 0 {
+		args = append(args, "--stop-signal="+r.stopSignal)
+	}
+	if r.stopTimeout >= 0 {
+		args = append(args, "--stop-timeout="+durationSeconds(r.stopTimeout))
+	}
+	if len(r.pidMode) > 0 {
+		args = append(args, "--pid="+r.pidMode)
+	}
+	if len(r.ipcMode) > 0 {
+		args = append(args, "--ipc="+r.ipcMode)
+	}
+	if len(r.platform) > 0 {
+		args = append(args, "--platform="+r.platform)
+	}
+	args = append(args, r.healthArgs()...)
+	args = append(args, r.logArgs()...)
+	for _, labelFile := range r.labelFiles {
+		args = append(args, "--label-file="+labelFile)
+	}
+	for _, label := range r.labels {
+		args = append(args, "--label", label)
 	}
 
+	for _, envFile := range r.envFiles {
+		args = append(args, "--env-file="+envFile)
+	}
+	args = append(args, r.secretEnvArgs(secretFile)...)
 	for _, env := range r.env {
 		args = append(args, "-e", env)
 	}
 
+	if r.publishAll {
+		args = append(args, "-P")
+	}
 	for _, port := range r.portMappings {
 		args = append(args, "-p", port)
 	}
@@ -227,14 +706,16 @@ func (r *DockerRun) Run(ctx context.Context) error {
 		args = append(args, "-v", bind)
 	}
 
+	for _, m := range r.mounts {
+		args = append(args, "--mount", m)
+	}
+
 	args = append(args, r.image)
+	if len(r.entrypoint) > 1 {
+		args = append(args, r.entrypoint[1:]...)
+	}
 	if len(r.args) > 0 {
 		args = append(args, r.args...)
 	}
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-		return r.d.Command(args...).Run(ctx)
-	}
+	return args
 }
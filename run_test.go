@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/saylorsolutions/modmake"
 	"github.com/stretchr/testify/assert"
@@ -179,3 +180,115 @@ func TestDockerRun_Run_Args(t *testing.T) {
 	assert.Error(t, err)
 	assert.Equal(t, "dry run: docker run some-image:latest cmd arg1", err.Error())
 }
+
+func TestDockerRun_Run_Entrypoint(t *testing.T) {
+	ctx := context.Background()
+	err := Docker().Dry().Run("some-image:latest", "extra").
+		Entrypoint("sh", "-c", "echo hello world").
+		Run(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --entrypoint=sh some-image:latest -c echo hello world extra", err.Error())
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").Entrypoint("") })
+}
+
+func TestDockerRun_Run_Process(t *testing.T) {
+	ctx := context.Background()
+	err := Docker().Dry().Run("some-image:latest").
+		Init().
+		StopSignal("SIGINT").
+		StopTimeout(0).
+		PIDMode("host").
+		IPCMode("container:other").
+		Platform("linux/arm64/v8").
+		Run(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --init --stop-signal=SIGINT --stop-timeout=0 --pid=host --ipc=container:other --platform=linux/arm64/v8 some-image:latest", err.Error())
+}
+
+func TestDockerRun_Process_Invalid(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest")
+	assert.Panics(t, func() { run.StopSignal("") })
+	assert.Panics(t, func() { run.StopTimeout(-time.Second) })
+	assert.Panics(t, func() { run.PIDMode("private") })
+	assert.Panics(t, func() { run.PIDMode("container:") })
+	assert.Panics(t, func() { run.IPCMode("shared") })
+	assert.Panics(t, func() { run.Platform("linux//amd64") })
+	assert.Panics(t, func() { run.Platform("linux/arm64/v8/extra") })
+}
+
+func TestDockerRun_Run_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Docker().Dry().Run("some-image:latest").Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDockerRun_Run_CancellableContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := Docker().Dry().Run("some-image:latest").
+		CancelGracePeriod(5 * time.Second).
+		RemoveOnCancel().
+		Run(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run some-image:latest", err.Error())
+}
+
+func TestDockerRun_Run_CancelledBeforeCreated(t *testing.T) {
+	// The container ID is only written after a delay, like when the image is pulled first.
+	d, calls := fakeDocker(t, `
+dir=$(dirname "$0")
+case "$1" in
+run)
+  for arg in "$@"; do
+    case "$arg" in --cidfile=*) cidfile="${arg#--cidfile=}" ;; esac
+  done
+  sleep 0.3
+  echo 0123456789ab > "$cidfile"
+  echo $$ > "$dir/pid"
+  exec sleep 10
+  ;;
+stop) kill "$(cat "$dir/pid")" ;;
+esac`)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := d.Run("some-image:latest").
+		Name("some-container").
+		CancelGracePeriod(5 * time.Second).
+		RemoveOnCancel().
+		Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	lines := calls()
+	if assert.Len(t, lines, 3) {
+		assert.Regexp(t, `^run --cidfile=\S+ --name=some-container some-image:latest$`, lines[0])
+		assert.Equal(t, "stop -t 5 0123456789ab", lines[1])
+		assert.Equal(t, "rm -f 0123456789ab", lines[2])
+	}
+}
+
+func TestDockerRun_Run_NotReady(t *testing.T) {
+	d, calls := fakeDocker(t, `
+case "$1" in
+run) echo 0123456789ab ;;
+inspect) echo running ;;
+exec) exit 1 ;;
+esac`)
+	err := d.Run("some-image:latest").
+		Name("some-container").
+		WaitFor(WaitForExec("pg_isready").Timeout(100*time.Millisecond).Backoff(10*time.Millisecond, 10*time.Millisecond)).
+		CancelGracePeriod(5 * time.Second).
+		RemoveOnCancel().
+		Run(context.Background())
+	assert.ErrorContains(t, err, "timed out waiting for exec 'pg_isready'")
+	lines := calls()
+	if assert.GreaterOrEqual(t, len(lines), 3) {
+		assert.Equal(t, "run --name=some-container -d some-image:latest", lines[0])
+		assert.Equal(t, []string{"stop -t 5 0123456789ab", "rm -f 0123456789ab"}, lines[len(lines)-2:])
+	}
+}
+
+func TestDockerRun_CancelGracePeriod_Invalid(t *testing.T) {
+	assert.Panics(t, func() {
+		Docker().Dry().Run("some-image:latest").CancelGracePeriod(-time.Second)
+	})
+}
@@ -0,0 +1,197 @@
+package modmake_docker
+
+import (
+	"strings"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// Capability is a Linux capability that may be added to or dropped from a container with [DockerRun.CapAdd] and [DockerRun.CapDrop].
+type Capability string
+
+const (
+	CapAll               Capability = "ALL" // CapAll refers to every capability, and is usually used to drop everything before adding back what's needed.
+	CapAuditControl      Capability = "AUDIT_CONTROL"
+	CapAuditRead         Capability = "AUDIT_READ"
+	CapAuditWrite        Capability = "AUDIT_WRITE"
+	CapBlockSuspend      Capability = "BLOCK_SUSPEND"
+	CapBPF               Capability = "BPF"
+	CapCheckpointRestore Capability = "CHECKPOINT_RESTORE"
+	CapChown             Capability = "CHOWN"
+	CapDacOverride       Capability = "DAC_OVERRIDE"
+	CapDacReadSearch     Capability = "DAC_READ_SEARCH"
+	CapFowner            Capability = "FOWNER"
+	CapFsetid            Capability = "FSETID"
+	CapIPCLock           Capability = "IPC_LOCK"
+	CapIPCOwner          Capability = "IPC_OWNER"
+	CapKill              Capability = "KILL"
+	CapLease             Capability = "LEASE"
+	CapLinuxImmutable    Capability = "LINUX_IMMUTABLE"
+	CapMacAdmin          Capability = "MAC_ADMIN"
+	CapMacOverride       Capability = "MAC_OVERRIDE"
+	CapMknod             Capability = "MKNOD"
+	CapNetAdmin          Capability = "NET_ADMIN"
+	CapNetBindService    Capability = "NET_BIND_SERVICE"
+	CapNetBroadcast      Capability = "NET_BROADCAST"
+	CapNetRaw            Capability = "NET_RAW"
+	CapPerfmon           Capability = "PERFMON"
+	CapSetfcap           Capability = "SETFCAP"
+	CapSetgid            Capability = "SETGID"
+	CapSetpcap           Capability = "SETPCAP"
+	CapSetuid            Capability = "SETUID"
+	CapSysAdmin          Capability = "SYS_ADMIN"
+	CapSysBoot           Capability = "SYS_BOOT"
+	CapSysChroot         Capability = "SYS_CHROOT"
+	CapSysModule         Capability = "SYS_MODULE"
+	CapSysNice           Capability = "SYS_NICE"
+	CapSysPacct          Capability = "SYS_PACCT"
+	CapSysPtrace         Capability = "SYS_PTRACE"
+	CapSysRawIO          Capability = "SYS_RAWIO"
+	CapSysResource       Capability = "SYS_RESOURCE"
+	CapSysTime           Capability = "SYS_TIME"
+	CapSysTTYConfig      Capability = "SYS_TTY_CONFIG"
+	CapSyslog            Capability = "SYSLOG"
+	CapWakeAlarm         Capability = "WAKE_ALARM"
+)
+
+var knownCapabilities = map[Capability]struct{}{
+	CapAll:               {},
+	CapAuditControl:      {},
+	CapAuditRead:         {},
+	CapAuditWrite:        {},
+	CapBlockSuspend:      {},
+	CapBPF:               {},
+	CapCheckpointRestore: {},
+	CapChown:             {},
+	CapDacOverride:       {},
+	CapDacReadSearch:     {},
+	CapFowner:            {},
+	CapFsetid:            {},
+	CapIPCLock:           {},
+	CapIPCOwner:          {},
+	CapKill:              {},
+	CapLease:             {},
+	CapLinuxImmutable:    {},
+	CapMacAdmin:          {},
+	CapMacOverride:       {},
+	CapMknod:             {},
+	CapNetAdmin:          {},
+	CapNetBindService:    {},
+	CapNetBroadcast:      {},
+	CapNetRaw:            {},
+	CapPerfmon:           {},
+	CapSetfcap:           {},
+	CapSetgid:            {},
+	CapSetpcap:           {},
+	CapSetuid:            {},
+	CapSysAdmin:          {},
+	CapSysBoot:           {},
+	CapSysChroot:         {},
+	CapSysModule:         {},
+	CapSysNice:           {},
+	CapSysPacct:          {},
+	CapSysPtrace:         {},
+	CapSysRawIO:          {},
+	CapSysResource:       {},
+	CapSysTime:           {},
+	CapSysTTYConfig:      {},
+	CapSyslog:            {},
+	CapWakeAlarm:         {},
+}
+
+// normalizeCapability accepts the same forms as Docker, like "net_admin" or "CAP_NET_ADMIN".
+func normalizeCapability(capability Capability) Capability {
+	normalized := Capability(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(string(capability))), "CAP_"))
+	if _, ok := knownCapabilities[normalized]; !ok {
+		panicf("unknown capability '%s'", capability)
+	}
+	return normalized
+}
+
+// User sets the user, and optionally the group, that the container process runs as.
+// This may be a name or ID, like "nobody", "1000", or "1000:1000".
+func (r *DockerRun) User(userGroup string) *DockerRun {
+	anyBlankPanic(strmap{"userGroup": &userGroup})
+	r.user = userGroup
+	return r
+}
+
+// GroupAdd adds supplementary groups for the container process, by name or ID.
+func (r *DockerRun) GroupAdd(groups ...string) *DockerRun {
+	for _, group := range groups {
+		// The loop variable is a copy, so the caller's slice isn't changed when it's trimmed.
+		anyBlankPanic(strmap{"group": &group})
+		r.security = appendMissing(r.security, "--group-add="+group)
+	}
+	return r
+}
+
+// CapAdd adds Linux capabilities to the container.
+func (r *DockerRun) CapAdd(capabilities ...Capability) *DockerRun {
+	for _, capability := range capabilities {
+		r.security = appendMissing(r.security, "--cap-add="+string(normalizeCapability(capability)))
+	}
+	return r
+}
+
+// CapDrop drops Linux capabilities from the container.
+// Use [CapAll] to drop every capability, then [DockerRun.CapAdd] to add back only what's needed.
+func (r *DockerRun) CapDrop(capabilities ...Capability) *DockerRun {
+	for _, capability := range capabilities {
+		r.security = appendMissing(r.security, "--cap-drop="+string(normalizeCapability(capability)))
+	}
+	return r
+}
+
+// SecurityOpt sets a security option as given to "--security-opt", like "label=disable".
+// Prefer the more specific methods, like [DockerRun.SeccompProfile], where they apply.
+func (r *DockerRun) SecurityOpt(opt string) *DockerRun {
+	anyBlankPanic(strmap{"opt": &opt})
+	r.security = appendMissing(r.security, "--security-opt="+opt)
+	return r
+}
+
+// SeccompProfile applies a seccomp profile from the host, which is resolved to an absolute path.
+func (r *DockerRun) SeccompProfile(profile PathString) *DockerRun {
+	absProfile, err := profile.Abs()
+	if err != nil {
+		panicf("failed to get absolute path for seccomp profile: %v", err)
+		return r
+	}
+	return r.SecurityOpt("seccomp=" + absProfile.String())
+}
+
+// SeccompUnconfined disables seccomp filtering for the container.
+func (r *DockerRun) SeccompUnconfined() *DockerRun {
+	return r.SecurityOpt("seccomp=unconfined")
+}
+
+// AppArmorProfile applies the named AppArmor profile, which must already be loaded on the host.
+func (r *DockerRun) AppArmorProfile(profile string) *DockerRun {
+	anyBlankPanic(strmap{"profile": &profile})
+	return r.SecurityOpt("apparmor=" + profile)
+}
+
+// NoNewPrivileges prevents the container process from gaining privileges, like with setuid binaries.
+func (r *DockerRun) NoNewPrivileges() *DockerRun {
+	return r.SecurityOpt("no-new-privileges")
+}
+
+// UsernsMode sets the user namespace mode of the container.
+// The only value Docker currently supports is "host", which disables user namespace remapping for the container.
+func (r *DockerRun) UsernsMode(mode string) *DockerRun {
+	anyBlankPanic(strmap{"mode": &mode})
+	r.security = appendMissing(r.security, "--userns="+mode)
+	return r
+}
+
+// Hardened locks down the container with a read only file system, no capabilities, and no privilege escalation.
+// Capabilities the container needs can be added back with [DockerRun.CapAdd].
+// This panics if the container is already privileged.
+func (r *DockerRun) Hardened() *DockerRun {
+	if r.privileged {
+		panic("a privileged container cannot be hardened")
+	}
+	r.hardened = true
+	return r.ReadOnlyFS().CapDrop(CapAll).NoNewPrivileges()
+}
@@ -0,0 +1,53 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/saylorsolutions/modmake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRun_Run_Security(t *testing.T) {
+	profile, _ := Path("./seccomp.json").Abs()
+	err := Docker().Dry().Run("some-image:latest").
+		User("1000:1000").
+		GroupAdd("audio", "video").
+		CapDrop(CapAll).
+		CapAdd(CapNetBindService, "chown", "CAP_KILL").
+		SeccompProfile(profile).
+		AppArmorProfile("docker-default").
+		UsernsMode("host").
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, fmt.Sprintf("dry run: docker run -u 1000:1000 --group-add=audio --group-add=video --cap-drop=ALL --cap-add=NET_BIND_SERVICE --cap-add=CHOWN --cap-add=KILL --security-opt=seccomp=%s --security-opt=apparmor=docker-default --userns=host some-image:latest", profile.String()), err.Error())
+}
+
+func TestDockerRun_Run_Hardened(t *testing.T) {
+	err := Docker().Dry().Run("some-image:latest").
+		Hardened().
+		NoNewPrivileges().
+		CapAdd(CapNetBindService).
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --read-only --cap-drop=ALL --security-opt=no-new-privileges --cap-add=NET_BIND_SERVICE some-image:latest", err.Error())
+}
+
+func TestDockerRun_Security_Invalid(t *testing.T) {
+	run := Docker().Dry().Run("some-image:latest")
+	assert.Panics(t, func() { run.User("") })
+	assert.Panics(t, func() { run.CapAdd("MAKE_COFFEE") })
+	assert.Panics(t, func() { run.SecurityOpt(" ") })
+	assert.Panics(t, func() { run.AppArmorProfile("") })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").PrivilegedContainer().Hardened() })
+	assert.Panics(t, func() { Docker().Dry().Run("some-image:latest").Hardened().PrivilegedContainer() })
+}
+
+func TestDockerRun_GroupAdd_CallerSlice(t *testing.T) {
+	groups := []string{" audio ", "video"}
+	err := Docker().Dry().Run("some-image:latest").GroupAdd(groups...).Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run --group-add=audio --group-add=video some-image:latest", err.Error())
+	assert.Equal(t, []string{" audio ", "video"}, groups, "Should not have changed the caller's slice")
+}
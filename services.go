@@ -0,0 +1,282 @@
+package modmake_docker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+const (
+	defaultReadyTimeout   = time.Minute
+	defaultCleanupTimeout = time.Minute
+)
+
+// Services creates a new [DockerServices], used to run a set of service containers for the duration of a Task.
+// This is useful for integration tests that depend on things like a database or a cache.
+func (d *DockerRef) Services() *DockerServices {
+	return &DockerServices{
+		d:            d,
+		readyTimeout: defaultReadyTimeout,
+	}
+}
+
+// DockerServices starts a set of [DockerRun] containers on a dedicated network, waits for them to be ready, and removes them afterward.
+// A service without any [WaitStrategy] is ready once it's running.
+// Containers and the network are removed even if the wrapped Task fails, or the context is cancelled.
+// If anything fails, then the logs of each service container are written to STDERR before it's removed.
+type DockerServices struct {
+	d            *DockerRef
+	network      string
+	readyTimeout time.Duration
+	noLogs       bool
+	services     []*service
+}
+
+type service struct {
+	name string
+	run  *DockerRun
+}
+
+// Network sets the name of the network that will be created for the services.
+// By default, a unique network name is generated each time the services are started.
+func (s *DockerServices) Network(name string) *DockerServices {
+	anyBlankPanic(strmap{"name": &name})
+	s.network = name
+	return s
+}
+
+// ReadyTimeout sets how long to wait for each service container to be ready, according to the strategies set with [DockerRun.WaitFor].
+// The default is 1 minute.
+func (s *DockerServices) ReadyTimeout(timeout time.Duration) *DockerServices {
+	if timeout <= 0 {
+		panicf("invalid ready timeout '%s'", timeout)
+		return s
+	}
+	s.readyTimeout = timeout
+	return s
+}
+
+// NoFailureLogs will prevent dumping service container logs to STDERR when starting the services or the wrapped Task fails.
+func (s *DockerServices) NoFailureLogs() *DockerServices {
+	s.noLogs = true
+	return s
+}
+
+// Add will add a service container to be started, in the order added.
+// The container is always run detached, and connected to the services network with the service name as its network alias.
+// Any networks passed to [DockerRun.ConnectNetwork] are connected as well, before the container is started.
+// If the container isn't given a name with [DockerRun.Name], then one is generated from the network and service name.
+// Streams can't be supplied to a service container, use [Container.Logs] with [ServiceEnv.Container] to read its output instead.
+func (s *DockerServices) Add(name string, run *DockerRun) *DockerServices {
+	anyBlankPanic(strmap{"name": &name})
+	if run == nil {
+		panicf("%s: nil run", name)
+		return s
+	}
+	if err := run.detachedStreamErr(); err != nil {
+		panicf("%s: %s", name, err)
+		return s
+	}
+	for _, svc := range s.services {
+		if svc.name == name {
+			panicf("duplicate service name '%s'", name)
+			return s
+		}
+	}
+	s.services = append(s.services, &service{name: name, run: run})
+	return s
+}
+
+// Around will run r while the services are running.
+func (s *DockerServices) Around(r Runner) Task {
+	return s.With(func(ctx context.Context, _ *ServiceEnv) error {
+		return r.Run(ctx)
+	})
+}
+
+// With will call fn while the services are running.
+// The [ServiceEnv] passed to fn can be used to find the host ports published by each service.
+func (s *DockerServices) With(fn func(ctx context.Context, env *ServiceEnv) error) Task {
+	return func(ctx context.Context) (err error) {
+		network := s.network
+		if len(network) == 0 {
+			network, err = uniqueName("modmake-services")
+			if err != nil {
+				return err
+			}
+		}
+		env := &ServiceEnv{
+			network:    network,
+			containers: map[string]*Container{},
+		}
+		if err := s.d.Network(network).Create().Run(ctx); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil && !s.noLogs {
+				s.dumpLogs(env)
+			}
+			if cleanupErr := s.cleanup(env); cleanupErr != nil {
+				err = errors.Join(err, cleanupErr)
+			}
+		}()
+
+		for _, svc := range s.services {
+			// Work on a copy so the configured DockerRun can be started again with a different network.
+			run := *svc.run
+			run.networkAliases = append([]string(nil), svc.run.networkAliases...)
+			run.extraNetworks = append([]networkEndpoint(nil), svc.run.extraNetworks...)
+			if len(run.name) == 0 {
+				run.name = network + "-" + svc.name
+			}
+			run.detached = true
+			if len(run.networkConn) > 0 && run.networkConn != network {
+				// The configured network is connected after the container is created, keeping its aliases and addresses.
+				primary := networkEndpoint{network: run.networkConn, aliases: run.networkAliases, ipv4: run.ipv4, ipv6: run.ipv6}
+				run.extraNetworks = append([]networkEndpoint{primary}, run.extraNetworks...)
+				run.networkAliases, run.ipv4, run.ipv6 = nil, "", ""
+			}
+			run.networkConn = network
+			run.networkAliases = appendMissing(run.networkAliases, svc.name)
+			// Readiness is checked here, so the ready timeout doesn't include the time to pull the image.
+			run.waits = nil
+			container, err := run.Start(ctx)
+			if container != nil {
+				// The container may exist even if it failed to start, and it still needs to be removed.
+				env.started = append(env.started, container)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to start service '%s': %w", svc.name, err)
+			}
+			env.containers[svc.name] = container
+			if err := s.waitReady(ctx, svc.name, container.ID(), svc.run.waits); err != nil {
+				return err
+			}
+		}
+		return fn(ctx, env)
+	}
+}
+
+func (s *DockerServices) waitReady(ctx context.Context, svcName, container string, strategies []WaitStrategy) error {
+	ctx, cancel := context.WithTimeout(ctx, s.readyTimeout)
+	defer cancel()
+	if len(strategies) == 0 {
+		strategies = []WaitStrategy{WaitForRunning()}
+	}
+	if err := waitAll(ctx, s.d, container, strategies); err != nil {
+		return fmt.Errorf("service '%s' was not ready: %w", svcName, err)
+	}
+	return nil
+}
+
+// dumpLogs writes the logs of all started service containers to STDERR, to help diagnose a failure.
+func (s *DockerServices) dumpLogs(env *ServiceEnv) {
+	if len(env.started) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCleanupTimeout)
+	defer cancel()
+	names := make([]string, len(env.started))
+	for i, c := range env.started {
+		names[i] = c.String()
+	}
+	_, _ = fmt.Fprintln(os.Stderr, "Service container logs:")
+	_ = s.d.Logs(names...).Prefix().Output(os.Stderr).Run(ctx)
+}
+
+// cleanup removes service containers in reverse start order, then the network.
+// A fresh context is used so cleanup still happens when the build context has been cancelled.
+func (s *DockerServices) cleanup(env *ServiceEnv) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCleanupTimeout)
+	defer cancel()
+	var errs []error
+	for i := len(env.started) - 1; i >= 0; i-- {
+		if err := env.started[i].Remove().Force().Run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove service container '%s': %w", env.started[i], err))
+		}
+	}
+	if err := s.d.Network(env.network).Remove().Run(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to remove network '%s': %w", env.network, err))
+	}
+	return errors.Join(errs...)
+}
+
+// ServiceEnv provides details about running service containers started by [DockerServices].
+type ServiceEnv struct {
+	network    string
+	started    []*Container
+	containers map[string]*Container
+}
+
+// Network returns the name of the network the services are connected to.
+func (e *ServiceEnv) Network() string {
+	return e.network
+}
+
+// Container returns the [Container] handle of the named service, or nil if there is no such service.
+func (e *ServiceEnv) Container(service string) *Container {
+	return e.containers[service]
+}
+
+// HostPort returns the host port published for the given container port of the named service.
+func (e *ServiceEnv) HostPort(ctx context.Context, service string, containerPort int) (int, error) {
+	container, ok := e.containers[service]
+	if !ok {
+		return 0, fmt.Errorf("unknown service '%s'", service)
+	}
+	return container.Port(ctx, containerPort)
+}
+
+// hostBinding uses "docker port" to find the host address and port bound to a container port.
+func (d *DockerRef) hostBinding(ctx context.Context, container, containerPort string) (string, int, error) {
+	out, err := d.output(ctx, "port", container, containerPort)
+	if err != nil {
+		return "", 0, err
+	}
+	return parseHostBinding(out)
+}
+
+// parseHostBinding parses the first binding in the output of "docker port", which looks like "0.0.0.0:49153".
+// Wildcard addresses are translated to the loopback address so the result can be used to connect to the port.
+func parseHostBinding(out string) (string, int, error) {
+	line := strings.TrimSpace(strings.SplitN(out, "\n", 2)[0])
+	idx := strings.LastIndex(line, ":")
+	if idx < 0 {
+		return "", 0, fmt.Errorf("unable to parse port binding '%s'", line)
+	}
+	port, err := strconv.Atoi(line[idx+1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("unable to parse port binding '%s': %w", line, err)
+	}
+	host := strings.Trim(line[:idx], "[]")
+	switch host {
+	case "", "0.0.0.0", "::":
+		host = "127.0.0.1"
+	}
+	return host, port, nil
+}
+
+func uniqueName(prefix string) (string, error) {
+	var buf [6]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("failed to generate unique name: %w", err)
+	}
+	return prefix + "-" + hex.EncodeToString(buf[:]), nil
+}
+
+func appendMissing(vals []string, val string) []string {
+	for _, v := range vals {
+		if v == val {
+			return vals
+		}
+	}
+	return append(vals, val)
+}
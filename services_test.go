@@ -0,0 +1,71 @@
+package modmake_docker
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/saylorsolutions/modmake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerServices_Network(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Services().Network("testnet").Add("db", d.Run("postgres:16")).Around(NoOp()),
+		"docker network create testnet")
+}
+
+func TestDockerServices_GeneratedNetwork(t *testing.T) {
+	d := Docker().Dry()
+	called := false
+	err := d.Services().Add("db", d.Run("postgres:16")).With(func(ctx context.Context, env *ServiceEnv) error {
+		called = true
+		return nil
+	}).Run(context.Background())
+	assert.Error(t, err)
+	assert.False(t, called, "Should not have called the wrapped function")
+	assert.Regexp(t, `^dry run: docker network create modmake-services-[0-9a-f]{12}$`, err.Error())
+}
+
+func TestDockerServices_UserNetwork(t *testing.T) {
+	d, calls := fakeDocker(t, `
+case "$1" in
+create) echo 0123456789ab ;;
+inspect) echo running ;;
+esac`)
+	err := d.Services().Network("testnet").
+		Add("db", d.Run("postgres:16").ConnectNetwork("usernet", "postgres").IP("172.20.0.5")).
+		Around(NoOp()).
+		Run(context.Background())
+	assert.NoError(t, err)
+	lines := calls()
+	if assert.GreaterOrEqual(t, len(lines), 4) {
+		assert.Equal(t, []string{
+			"network create testnet",
+			"create --name=testnet-db --network=testnet --network-alias=db postgres:16",
+			"network connect --alias=postgres --ip=172.20.0.5 usernet 0123456789ab",
+			"start 0123456789ab",
+		}, lines[:4])
+	}
+}
+
+func TestDockerServices_DuplicateService(t *testing.T) {
+	d := Docker().Dry()
+	assert.Panics(t, func() {
+		d.Services().Add("db", d.Run("postgres:16")).Add("db", d.Run("postgres:16"))
+	})
+}
+
+func TestParseHostBinding(t *testing.T) {
+	host, port, err := parseHostBinding("0.0.0.0:49153\n[::]:49153")
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", host)
+	assert.Equal(t, 49153, port)
+
+	host, port, err = parseHostBinding("192.168.1.5:8080")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.5", host)
+	assert.Equal(t, 8080, port)
+
+	_, _, err = parseHostBinding("")
+	assert.Error(t, err)
+}
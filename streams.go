@@ -0,0 +1,102 @@
+package modmake_docker
+
+import (
+	"errors"
+	"io"
+)
+
+// Stdin will pass everything read from stdin to the container's STDIN, which is closed once stdin is exhausted.
+// This isn't compatible with [DockerRun.InteractiveTerminal] or [DockerRun.Detached].
+func (r *DockerRun) Stdin(stdin io.Reader) *DockerRun {
+	if stdin == nil {
+		panic("nil stdin reader")
+	}
+	if r.interactive || r.detached {
+		panic("stdin cannot be supplied to an interactive terminal or detached container")
+	}
+	r.stdin = stdin
+	return r
+}
+
+// Stdout will write the container's STDOUT to stdout, instead of this process' STDOUT.
+// This isn't compatible with [DockerRun.Detached], use [DockerRef.Logs] to read the output of a detached container instead.
+func (r *DockerRun) Stdout(stdout io.Writer) *DockerRun {
+	if stdout == nil {
+		panic("nil stdout writer")
+	}
+	if r.detached {
+		panic("stdout cannot be supplied to a detached container")
+	}
+	r.stdout = stdout
+	return r
+}
+
+// Stderr will write the container's STDERR to stderr, instead of this process' STDERR.
+// This isn't compatible with [DockerRun.Detached], use [DockerRef.Logs] to read the output of a detached container instead.
+func (r *DockerRun) Stderr(stderr io.Writer) *DockerRun {
+	if stderr == nil {
+		panic("nil stderr writer")
+	}
+	if r.detached {
+		panic("stderr cannot be supplied to a detached container")
+	}
+	r.stderr = stderr
+	return r
+}
+
+// detachedStreamErr returns an error if any streams were supplied, since they can't be used with a detached container.
+func (r *DockerRun) detachedStreamErr() error {
+	switch {
+	case r.stdin != nil:
+		return errors.New("stdin cannot be supplied to a detached container")
+	case r.stdout != nil, r.stderr != nil:
+		return errors.New("stdout and stderr cannot be supplied to a detached container, use logs to read its output instead")
+	}
+	return nil
+}
+
+func (r *DockerRun) detachedStreamPanic() {
+	if err := r.detachedStreamErr(); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Stdin will pass everything read from stdin to the command's STDIN, which is closed once stdin is exhausted.
+// This isn't compatible with [DockerExec.InteractiveTerm] or [DockerExec.Detached].
+func (e *DockerExec) Stdin(stdin io.Reader) *DockerExec {
+	if stdin == nil {
+		panic("nil stdin reader")
+	}
+	if e.tty || e.detached {
+		panic("stdin cannot be supplied to an interactive terminal or detached command")
+	}
+	e.interactive = true
+	e.stdin = stdin
+	return e
+}
+
+// Stdout will write the command's STDOUT to stdout, instead of this process' STDOUT.
+// This isn't compatible with [DockerExec.Detached].
+func (e *DockerExec) Stdout(stdout io.Writer) *DockerExec {
+	if stdout == nil {
+		panic("nil stdout writer")
+	}
+	if e.detached {
+		panic("stdout cannot be supplied to a detached command")
+	}
+	e.stdout = stdout
+	return e
+}
+
+// Stderr will write the command's STDERR to stderr, instead of this process' STDERR.
+// This isn't compatible with [DockerExec.Detached].
+func (e *DockerExec) Stderr(stderr io.Writer) *DockerExec {
+	if stderr == nil {
+		panic("nil stderr writer")
+	}
+	if e.detached {
+		panic("stderr cannot be supplied to a detached command")
+	}
+	e.stderr = stderr
+	return e
+}
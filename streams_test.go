@@ -0,0 +1,59 @@
+package modmake_docker
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRun_Run_Stdin(t *testing.T) {
+	var out bytes.Buffer
+	err := Docker().Dry().Run("postgres:16", "psql").
+		Stdin(strings.NewReader("select 1;")).
+		Stdout(&out).
+		Stderr(&out).
+		Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "dry run: docker run -i postgres:16 psql", err.Error())
+}
+
+func TestDockerRun_Stdin_Detached(t *testing.T) {
+	var out bytes.Buffer
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Stdin(strings.NewReader("")).Detached() })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Stdout(&out).Detached() })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Stderr(&out).WaitFor(WaitForRunning()) })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Detached().Stdin(strings.NewReader("")) })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Detached().Stdout(&out) })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Detached().Stderr(&out) })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").InteractiveTerminal().Stdin(strings.NewReader("")) })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Stdin(strings.NewReader("")).InteractiveTerminal() })
+	assert.Panics(t, func() { Docker().Dry().Run("postgres:16").Stdout(nil) })
+}
+
+func TestDockerRun_Start_Streams(t *testing.T) {
+	var out bytes.Buffer
+	d := Docker().Dry()
+	_, err := d.Run("postgres:16").Stdout(&out).Start(context.Background())
+	assert.EqualError(t, err, "stdout and stderr cannot be supplied to a detached container, use logs to read its output instead")
+	_, err = d.Run("postgres:16").Stdin(strings.NewReader("select 1;")).Start(context.Background())
+	assert.EqualError(t, err, "stdin cannot be supplied to a detached container")
+	assert.Panics(t, func() { d.Services().Add("db", d.Run("postgres:16").Stderr(&out)) })
+}
+
+func TestDockerExec_Streams(t *testing.T) {
+	var out bytes.Buffer
+	d := Docker().Dry()
+	isDryRunResult(t, d.Exec("db", "psql").Stdin(strings.NewReader("select 1;")).Stdout(&out).Task(), "docker exec -i db psql")
+	isDryRunResult(t, d.Exec("db", "pg_dump").Stdout(&out).Stderr(&out).Task(), "docker exec -i db pg_dump")
+	assert.Panics(t, func() { d.Exec("db", "psql").Detached().Stdin(strings.NewReader("")) })
+	assert.Panics(t, func() { d.Exec("db", "psql").InteractiveTerm().Stdin(strings.NewReader("")) })
+	assert.Panics(t, func() { d.Exec("db", "psql").Stdin(strings.NewReader("")).Detached() })
+	assert.Panics(t, func() { d.Exec("db", "cat").Stdout(&out).Detached() })
+	assert.Panics(t, func() { d.Exec("db", "cat").Stderr(&out).Detached() })
+	assert.Panics(t, func() { d.Exec("db", "cat").Detached().Stdout(&out) })
+	assert.Panics(t, func() { d.Exec("db", "cat").Detached().Stderr(&out) })
+	assert.Panics(t, func() { d.Exec("db", "psql").Stderr(nil) })
+}
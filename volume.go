@@ -0,0 +1,302 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+// Volume creates a [DockerVolume], used to manage the named volume.
+func (d *DockerRef) Volume(name string) *DockerVolume {
+	anyBlankPanic(strmap{"name": &name})
+	return &DockerVolume{d: d, name: name}
+}
+
+// DockerVolume references a named volume, and provides options for creating it.
+// The creation options have no effect on a volume that already exists.
+type DockerVolume struct {
+	d          *DockerRef
+	name       string
+	driver     string
+	driverOpts []string
+	labels     []string
+}
+
+// VolumeInfo is the result of inspecting a volume with [DockerVolume.Inspect].
+type VolumeInfo struct {
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver"`
+	Mountpoint string            `json:"Mountpoint"`
+	CreatedAt  time.Time         `json:"CreatedAt"`
+	Scope      string            `json:"Scope"`
+	Labels     map[string]string `json:"Labels"`
+	Options    map[string]string `json:"Options"`
+}
+
+// Name returns the name of the volume.
+func (v *DockerVolume) Name() string {
+	return v.name
+}
+
+// Driver sets the volume driver used when creating the volume.
+// The default is "local".
+func (v *DockerVolume) Driver(driver string) *DockerVolume {
+	anyBlankPanic(strmap{"driver": &driver})
+	v.driver = driver
+	return v
+}
+
+// DriverOpt sets a driver specific option used when creating the volume.
+func (v *DockerVolume) DriverOpt(key, val string) *DockerVolume {
+	anyBlankPanic(strmap{"key": &key, "val": &val})
+	v.driverOpts = append(v.driverOpts, fmt.Sprintf("%s=%s", key, val))
+	return v
+}
+
+// Label will set a metadata label on the created volume.
+func (v *DockerVolume) Label(key, val string) *DockerVolume {
+	anyBlankPanic(strmap{"key": &key, "val": &val})
+	v.labels = append(v.labels, fmt.Sprintf("%s=%s", key, val))
+	return v
+}
+
+// Create will create the volume.
+func (v *DockerVolume) Create() Task {
+	return func(ctx context.Context) error {
+		return v.d.Command(v.createArgs()...).Run(ctx)
+	}
+}
+
+func (v *DockerVolume) createArgs() []string {
+	args := []string{"volume", "create"}
+	if len(v.driver) > 0 {
+		args = append(args, "--driver="+v.driver)
+	}
+	for _, opt := range v.driverOpts {
+		args = append(args, "--opt", opt)
+	}
+	for _, label := range v.labels {
+		args = append(args, "--label", label)
+	}
+	return append(args, v.name)
+}
+
+// Ensure will create the volume if it doesn't already exist.
+func (v *DockerVolume) Ensure() Task {
+	return func(ctx context.Context) error {
+		exists, err := v.Exists(ctx)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+		return v.Create().Run(ctx)
+	}
+}
+
+// Exists reports whether the volume exists.
+func (v *DockerVolume) Exists(ctx context.Context) (bool, error) {
+	return v.d.objectExists(ctx, "volume", v.name)
+}
+
+// Inspect returns details about the volume.
+func (v *DockerVolume) Inspect(ctx context.Context) (*VolumeInfo, error) {
+	out, err := v.d.output(ctx, "volume", "inspect", v.name)
+	if err != nil {
+		return nil, err
+	}
+	return decodeInspect[VolumeInfo](out)
+}
+
+// Remove will remove the volume, which must not be in use by any container.
+func (v *DockerVolume) Remove() Task {
+	return v.d.Command("volume", "rm", v.name).Run
+}
+
+// MountConsistency is used with [DockerMount.Consistency] to set the consistency requirements of a bind mount.
+// This only has an effect with Docker Desktop on macOS.
+type MountConsistency string
+
+const (
+	ConsistencyConsistent MountConsistency = "consistent" // ConsistencyConsistent requires a perfectly consistent view between host and container. This is the default.
+	ConsistencyCached     MountConsistency = "cached"     // ConsistencyCached allows the container's view to lag behind the host.
+	ConsistencyDelegated  MountConsistency = "delegated"  // ConsistencyDelegated allows the host's view to lag behind the container.
+)
+
+var knownConsistencies = map[MountConsistency]struct{}{
+	ConsistencyConsistent: {},
+	ConsistencyCached:     {},
+	ConsistencyDelegated:  {},
+}
+
+// BindPropagation is used with [DockerMount.BindPropagation] to control whether mounts within a bind mount are propagated between host and container.
+type BindPropagation string
+
+const (
+	PropagationPrivate  BindPropagation = "private"
+	PropagationRPrivate BindPropagation = "rprivate" // PropagationRPrivate doesn't propagate mounts in either direction. This is the default.
+	PropagationShared   BindPropagation = "shared"
+	PropagationRShared  BindPropagation = "rshared"
+	PropagationSlave    BindPropagation = "slave"
+	PropagationRSlave   BindPropagation = "rslave"
+)
+
+var knownPropagations = map[BindPropagation]struct{}{
+	PropagationPrivate:  {},
+	PropagationRPrivate: {},
+	PropagationShared:   {},
+	PropagationRShared:  {},
+	PropagationSlave:    {},
+	PropagationRSlave:   {},
+}
+
+type mountType string
+
+const (
+	mountBind   mountType = "bind"
+	mountVolume mountType = "volume"
+	mountTmpfs  mountType = "tmpfs"
+)
+
+// DockerMount is a mount specification used with [DockerRun.Mount].
+type DockerMount struct {
+	mountType   mountType
+	source      string
+	target      string
+	readOnly    bool
+	consistency MountConsistency
+	propagation BindPropagation
+	tmpfsSize   ByteSize
+	tmpfsMode   os.FileMode
+}
+
+// MountBind creates a [DockerMount] for a host path, which is resolved to an absolute path.
+// This is the equivalent of [DockerRun.VolumeMount], with more options.
+func MountBind(hostPath, containerPath PathString) *DockerMount {
+	absHostPath, err := hostPath.Abs()
+	if err != nil {
+		panicf("failed to get absolute path for host bind mount: %v", err)
+		return nil
+	}
+	return newMount(mountBind, absHostPath.String(), containerPath)
+}
+
+// MountVolume creates a [DockerMount] for a named volume.
+// The volume will be created by Docker if it doesn't exist, see [DockerRef.Volume] for more control over its creation.
+func MountVolume(volume string, containerPath PathString) *DockerMount {
+	anyBlankPanic(strmap{"volume": &volume})
+	return newMount(mountVolume, volume, containerPath)
+}
+
+// MountTmpfs creates a [DockerMount] for an in-memory file system, which is discarded when the container stops.
+func MountTmpfs(containerPath PathString) *DockerMount {
+	return newMount(mountTmpfs, "", containerPath)
+}
+
+func newMount(typ mountType, source string, containerPath PathString) *DockerMount {
+	target := containerPath.ToSlash()
+	anyBlankPanic(strmap{"containerPath": &target})
+	return &DockerMount{mountType: typ, source: source, target: target}
+}
+
+// ReadOnly will mount the file system as read only in the container.
+func (m *DockerMount) ReadOnly() *DockerMount {
+	if m.mountType == mountTmpfs {
+		panic("read only is not supported for tmpfs mounts")
+	}
+	m.readOnly = true
+	return m
+}
+
+// Consistency sets the [MountConsistency] of a bind or volume mount.
+func (m *DockerMount) Consistency(consistency MountConsistency) *DockerMount {
+	if m.mountType == mountTmpfs {
+		panic("consistency is not supported for tmpfs mounts")
+	}
+	if _, ok := knownConsistencies[consistency]; !ok {
+		panicf("unknown mount consistency '%s'", consistency)
+		return m
+	}
+	m.consistency = consistency
+	return m
+}
+
+// BindPropagation sets the [BindPropagation] of a bind mount.
+func (m *DockerMount) BindPropagation(propagation BindPropagation) *DockerMount {
+	if m.mountType != mountBind {
+		panicf("bind propagation is not supported for %s mounts", m.mountType)
+		return m
+	}
+	if _, ok := knownPropagations[propagation]; !ok {
+		panicf("unknown bind propagation '%s'", propagation)
+		return m
+	}
+	m.propagation = propagation
+	return m
+}
+
+// TmpfsSize limits the size of a tmpfs mount.
+func (m *DockerMount) TmpfsSize(size ByteSize) *DockerMount {
+	if m.mountType != mountTmpfs {
+		panicf("tmpfs size is not supported for %s mounts", m.mountType)
+		return m
+	}
+	if size < 1 {
+		panicf("invalid tmpfs size '%s'", size)
+		return m
+	}
+	m.tmpfsSize = size
+	return m
+}
+
+// TmpfsMode sets the file mode of a tmpfs mount, like 0700.
+func (m *DockerMount) TmpfsMode(mode os.FileMode) *DockerMount {
+	if m.mountType != mountTmpfs {
+		panicf("tmpfs mode is not supported for %s mounts", m.mountType)
+		return m
+	}
+	m.tmpfsMode = mode.Perm()
+	return m
+}
+
+// String formats the mount as a value for the "--mount" flag.
+func (m *DockerMount) String() string {
+	fields := []string{"type=" + string(m.mountType)}
+	if len(m.source) > 0 {
+		fields = append(fields, "source="+m.source)
+	}
+	fields = append(fields, "target="+m.target)
+	if m.readOnly {
+		fields = append(fields, "readonly")
+	}
+	if len(m.consistency) > 0 {
+		fields = append(fields, "consistency="+string(m.consistency))
+	}
+	if len(m.propagation) > 0 {
+		fields = append(fields, "bind-propagation="+string(m.propagation))
+	}
+	if m.tmpfsSize > 0 {
+		fields = append(fields, "tmpfs-size="+strconv.FormatInt(int64(m.tmpfsSize), 10))
+	}
+	if m.tmpfsMode != 0 {
+		fields = append(fields, "tmpfs-mode="+strconv.FormatUint(uint64(m.tmpfsMode), 8))
+	}
+	for i, field := range fields {
+		fields[i] = csvField(field)
+	}
+	return strings.Join(fields, ",")
+}
+
+// csvField quotes a field if needed, since Docker parses "--mount" values as CSV.
+func csvField(field string) string {
+	if !strings.ContainsAny(field, ",\"\n") {
+		return field
+	}
+	return `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+}
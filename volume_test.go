@@ -0,0 +1,66 @@
+package modmake_docker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/saylorsolutions/modmake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerVolume(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Volume("gomod").Create(), "docker volume create gomod")
+	isDryRunResult(t, d.Volume("gomod").Driver("local").DriverOpt("type", "tmpfs").Label("project", "test").Create(),
+		"docker volume create --driver=local --opt type=tmpfs --label project=test gomod")
+	isDryRunResult(t, d.Volume("gomod").Ensure(), "docker volume ls --filter name=^gomod$ -q")
+	isDryRunResult(t, d.Volume("gomod").Remove(), "docker volume rm gomod")
+}
+
+func TestDockerVolume_Ensure_Missing(t *testing.T) {
+	d, calls := fakeDocker(t, `
+case "$1 $2" in
+"volume inspect") echo "Error response from daemon: get gomod: no such volume" >&2; exit 1 ;;
+"volume create") echo gomod ;;
+esac`)
+	exists, err := d.Volume("gomod").Exists(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	assert.NoError(t, d.Volume("gomod").Ensure().Run(context.Background()))
+	assert.Equal(t, []string{
+		"volume ls --filter name=^gomod$ -q",
+		"volume ls --filter name=^gomod$ -q",
+		"volume create gomod",
+	}, calls())
+}
+
+func TestDecodeInspect_Volume(t *testing.T) {
+	info, err := decodeInspect[VolumeInfo](`[{"CreatedAt": "2024-03-01T12:00:00Z", "Driver": "local", "Labels": {"project": "test"}, "Mountpoint": "/var/lib/docker/volumes/gomod/_data", "Name": "gomod", "Options": null, "Scope": "local"}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, "gomod", info.Name)
+	assert.Equal(t, "test", info.Labels["project"])
+}
+
+func TestDockerMount(t *testing.T) {
+	hostPath, _ := Path("./mount").Abs()
+	assert.Equal(t, fmt.Sprintf("type=bind,source=%s,target=/app,readonly,consistency=cached,bind-propagation=rslave", hostPath.String()),
+		MountBind(hostPath, "/app").ReadOnly().Consistency(ConsistencyCached).BindPropagation(PropagationRSlave).String())
+	assert.Equal(t, "type=volume,source=gomod,target=/go/pkg/mod", MountVolume("gomod", "/go/pkg/mod").String())
+	assert.Equal(t, "type=tmpfs,target=/scratch,tmpfs-size=67108864,tmpfs-mode=700", MountTmpfs("/scratch").TmpfsSize(64<<20).TmpfsMode(0700).String())
+	assert.Equal(t, `type=volume,"source=a,b",target=/data`, MountVolume("a,b", "/data").String())
+}
+
+func TestDockerMount_Invalid(t *testing.T) {
+	assert.Panics(t, func() { MountTmpfs("/scratch").ReadOnly() })
+	assert.Panics(t, func() { MountVolume("gomod", "/go/pkg/mod").BindPropagation(PropagationShared) })
+	assert.Panics(t, func() { MountVolume("gomod", "/go/pkg/mod").TmpfsSize(1024) })
+	assert.Panics(t, func() { MountVolume("gomod", "") })
+	assert.Panics(t, func() { MountBind(".", "/app").Consistency("sometimes") })
+}
+
+func TestDockerRun_Mount(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Run("some-image:latest").Mount(MountVolume("gomod", "/go/pkg/mod"), MountTmpfs("/tmp")).Task(),
+		"docker run --mount type=volume,source=gomod,target=/go/pkg/mod --mount type=tmpfs,target=/tmp some-image:latest")
+}
@@ -0,0 +1,247 @@
+package modmake_docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/saylorsolutions/modmake"
+)
+
+const (
+	defaultWaitTimeout    = time.Minute
+	defaultInitialBackoff = 250 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+var (
+	ErrContainerStopped = errors.New("container stopped before it was ready")
+	ErrNoHealthCheck    = errors.New("container has no health check")
+)
+
+// WaitStrategy determines when a running container is ready to be used.
+type WaitStrategy interface {
+	// WaitReady blocks until the container is ready, returning an error if it doesn't become ready in time.
+	WaitReady(ctx context.Context, d *DockerRef, container string) error
+}
+
+var _ WaitStrategy = (*DockerWait)(nil)
+
+// readyCheck reports whether a container is ready.
+// Returning an error means that the container will never be ready, and waiting should stop.
+type readyCheck func(ctx context.Context, d *DockerRef, container string) (bool, error)
+
+// DockerWait is a [WaitStrategy] that repeatedly checks a container with increasing delay until it's ready, or the timeout elapses.
+type DockerWait struct {
+	desc           string
+	check          readyCheck
+	timeout        time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func newWait(desc string, check readyCheck) *DockerWait {
+	return &DockerWait{
+		desc:           desc,
+		check:          check,
+		timeout:        defaultWaitTimeout,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+}
+
+// WaitForRunning waits for the container to be in the running state.
+// All other strategies include this check.
+func WaitForRunning() *DockerWait {
+	return newWait("running", func(ctx context.Context, d *DockerRef, container string) (bool, error) {
+		return true, nil
+	})
+}
+
+// WaitForPort waits for a TCP connection to be accepted on the host port published for the given container port, and kept open.
+// Docker's userland proxy accepts connections on a published port before anything in the container is listening, then closes them.
+// So a connection only counts if it isn't closed within a short time, or the container sends data first.
+// Use [WaitForLog] or [WaitForExec] for services that close idle connections, or that need more than a listening socket to be ready.
+func WaitForPort(containerPort int) *DockerWait {
+	if containerPort < 1 {
+		panicf("invalid port value '%d'", containerPort)
+		return nil
+	}
+	return newWait(fmt.Sprintf("port %d", containerPort), func(ctx context.Context, d *DockerRef, container string) (bool, error) {
+		host, port, err := d.hostBinding(ctx, container, strconv.Itoa(containerPort))
+		if err != nil {
+			return false, err
+		}
+		return probeTCP(ctx, net.JoinHostPort(host, strconv.Itoa(port))), nil
+	})
+}
+
+// portProbeDelay is how long a connection must stay open to count as accepted by the container, rather than the userland proxy.
+const portProbeDelay = 250 * time.Millisecond
+
+// probeTCP reports whether a TCP connection to addr is accepted, and not immediately closed.
+func probeTCP(ctx context.Context, addr string) bool {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	if err := conn.SetReadDeadline(time.Now().Add(portProbeDelay)); err != nil {
+		return false
+	}
+	var buf [1]byte
+	n, err := conn.Read(buf[:])
+	if n > 0 {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// WaitForLog waits for the given regular expression to match the container's logs.
+// Both STDOUT and STDERR logs are matched.
+func WaitForLog(pattern string) *DockerWait {
+	anyBlankPanic(strmap{"pattern": &pattern})
+	pat := regexp.MustCompile(pattern)
+	return newWait(fmt.Sprintf("log pattern '%s'", pattern), func(ctx context.Context, d *DockerRef, container string) (bool, error) {
+		var buf bytes.Buffer
+		if err := d.commandIO(nil, &buf, &buf, "logs", container).Run(ctx); err != nil {
+			return false, err
+		}
+		return pat.Match(buf.Bytes()), nil
+	})
+}
+
+// WaitForHealthy waits for the container's health status to be "healthy".
+// The container must have a health check, either from its image or [DockerRun.HealthCheck].
+func WaitForHealthy() *DockerWait {
+	return newWait("healthy", func(ctx context.Context, d *DockerRef, container string) (bool, error) {
+		status, err := d.output(ctx, "inspect", "-f", "{{if .State.Health}}{{.State.Health.Status}}{{end}}", container)
+		if err != nil {
+			return false, err
+		}
+		if len(status) == 0 {
+			return false, ErrNoHealthCheck
+		}
+		return status == "healthy", nil
+	})
+}
+
+// WaitForExec waits for a command executed in the container to exit with code 0.
+func WaitForExec(cmd string, args ...string) *DockerWait {
+	anyBlankPanic(strmap{"cmd": &cmd})
+	desc := strings.Join(append([]string{cmd}, args...), " ")
+	return newWait(fmt.Sprintf("exec '%s'", desc), func(ctx context.Context, d *DockerRef, container string) (bool, error) {
+		_, err := d.output(ctx, append([]string{"exec", container, cmd}, args...)...)
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+// Timeout sets the maximum time to wait for the container to be ready.
+// The default is 1 minute.
+func (w *DockerWait) Timeout(timeout time.Duration) *DockerWait {
+	if timeout <= 0 {
+		panicf("invalid wait timeout '%s'", timeout)
+		return w
+	}
+	w.timeout = timeout
+	return w
+}
+
+// Backoff sets the initial delay between checks, which is doubled after each failed check up to maxDelay.
+// The default is 250ms, up to 2s.
+func (w *DockerWait) Backoff(initial, maxDelay time.Duration) *DockerWait {
+	if initial <= 0 || maxDelay < initial {
+		panicf("invalid backoff '%s' to '%s'", initial, maxDelay)
+		return w
+	}
+	w.initialBackoff = initial
+	w.maxBackoff = maxDelay
+	return w
+}
+
+func (w *DockerWait) WaitReady(ctx context.Context, d *DockerRef, container string) error {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+	delay := w.initialBackoff
+	for {
+		ready, err := w.poll(ctx, d, container)
+		if err != nil && ctx.Err() != nil {
+			return fmt.Errorf("timed out waiting for %s in container '%s': %w", w.desc, container, ctx.Err())
+		}
+		if err != nil {
+			return fmt.Errorf("failed waiting for %s in container '%s': %w", w.desc, container, err)
+		}
+		if ready {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("timed out waiting for %s in container '%s': %w", w.desc, container, ctx.Err())
+		case <-timer.C:
+		}
+		delay = nextBackoff(delay, w.maxBackoff)
+	}
+}
+
+func (w *DockerWait) poll(ctx context.Context, d *DockerRef, container string) (bool, error) {
+	status, err := d.output(ctx, "inspect", "-f", "{{.State.Status}}", container)
+	if err != nil {
+		return false, err
+	}
+	switch status {
+	case "running":
+		return w.check(ctx, d, container)
+	case "exited", "dead":
+		return false, ErrContainerStopped
+	default:
+		return false, nil
+	}
+}
+
+func nextBackoff(current, maxDelay time.Duration) time.Duration {
+	next := current * 2
+	if next > maxDelay {
+		return maxDelay
+	}
+	return next
+}
+
+// WaitFor creates a Task that waits for a running container to be ready, according to each [WaitStrategy] in order.
+func (d *DockerRef) WaitFor(container string, strategies ...WaitStrategy) Task {
+	anyBlankPanic(strmap{"container": &container})
+	if len(strategies) == 0 {
+		strategies = []WaitStrategy{WaitForRunning()}
+	}
+	return func(ctx context.Context) error {
+		return waitAll(ctx, d, container, strategies)
+	}
+}
+
+func waitAll(ctx context.Context, d *DockerRef, container string, strategies []WaitStrategy) error {
+	for _, strategy := range strategies {
+		if err := strategy.WaitReady(ctx, d, container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package modmake_docker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDockerRef_WaitFor(t *testing.T) {
+	d := Docker().Dry()
+	err := d.WaitFor("some-container", WaitForHealthy()).Run(context.Background())
+	assert.Error(t, err)
+	var dryRun *DryRunResult
+	assert.True(t, errors.As(err, &dryRun), "Should have been a dry run error")
+	assert.Equal(t, []string{"inspect", "-f", "{{.State.Status}}", "some-container"}, dryRun.Args())
+}
+
+func TestDockerRun_WaitFor(t *testing.T) {
+	d := Docker().Dry()
+	isDryRunResult(t, d.Run("some-image:latest").WaitFor(WaitForPort(8080)).Task(),
+		"docker run -d some-image:latest")
+}
+
+func TestDockerWait_Invalid(t *testing.T) {
+	assert.Panics(t, func() { WaitForPort(0) })
+	assert.Panics(t, func() { WaitForLog("") })
+	assert.Panics(t, func() { WaitForLog("(") })
+	assert.Panics(t, func() { WaitForExec(" ") })
+	assert.Panics(t, func() { WaitForRunning().Timeout(0) })
+	assert.Panics(t, func() { WaitForRunning().Backoff(time.Second, time.Millisecond) })
+}
+
+func TestNextBackoff(t *testing.T) {
+	assert.Equal(t, 500*time.Millisecond, nextBackoff(250*time.Millisecond, 2*time.Second))
+	assert.Equal(t, 2*time.Second, nextBackoff(1500*time.Millisecond, 2*time.Second))
+}
+
+func TestProbeTCP(t *testing.T) {
+	// Accepts connections and closes them right away, like the userland proxy when nothing is listening in the container.
+	closing := listenTCP(t, func(conn net.Conn) { _ = conn.Close() })
+	assert.False(t, probeTCP(context.Background(), closing))
+
+	// Accepts connections and waits for the client, like a listening service.
+	holding := listenTCP(t, func(conn net.Conn) {
+		time.Sleep(2 * portProbeDelay)
+		_ = conn.Close()
+	})
+	assert.True(t, probeTCP(context.Background(), holding))
+
+	// Sends a greeting, like an SSH or SMTP server.
+	greeting := listenTCP(t, func(conn net.Conn) {
+		_, _ = conn.Write([]byte("220 ready\r\n"))
+		_ = conn.Close()
+	})
+	assert.True(t, probeTCP(context.Background(), greeting))
+}
+
+func listenTCP(t *testing.T, handle func(conn net.Conn)) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+	return l.Addr().String()
+}